@@ -0,0 +1,54 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CommunityAliasSpec defines the community a CommunityAlias stands for.
+type CommunityAliasSpec struct {
+	// Value is the community the alias stands for, in any of the forms accepted
+	// by the FRRConfigurations (legacy, large, extended or well-known name).
+	Value string `json:"value"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Value",type=string,JSONPath=`.spec.value`
+
+// CommunityAlias maps a name to a BGP community. The name of the object can be used
+// in place of the community in the FRRConfigurations.
+type CommunityAlias struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CommunityAliasSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CommunityAliasList contains a list of CommunityAlias.
+type CommunityAliasList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CommunityAlias `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CommunityAlias{}, &CommunityAliasList{})
+}
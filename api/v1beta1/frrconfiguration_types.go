@@ -26,6 +26,13 @@ type FRRConfigurationSpec struct {
 	// +optional
 	BGP BGPConfig `json:"bgp,omitempty"`
 
+	// +optional
+	OSPF OSPFConfig `json:"ospf,omitempty"`
+
+	// The list of static routes, grouped by VRF.
+	// +optional
+	StaticRoutes []StaticRoutes `json:"staticRoutes,omitempty"`
+
 	// +optional
 	Raw RawConfig `json:"raw,omitempty"`
 	// Limits the nodes that will attempt to apply this config.
@@ -54,15 +61,165 @@ type BGPConfig struct {
 	// The list of bfd profiles to be used when configuring the neighbors.
 	// +optional
 	BFDProfiles []BFDProfile `json:"bfdProfiles,omitempty"`
+	// The list of neighbor templates the neighbors can inherit their
+	// properties from. As for the bfd profiles, a template can be
+	// referenced by the neighbors of any configuration.
+	// +optional
+	NeighborTemplates []NeighborTemplate `json:"neighborTemplates,omitempty"`
+	// Maintenance configures how the advertisements change while the node is in
+	// maintenance, that is when the node is cordoned or has the
+	// frrk8s.metallb.io/maintenance annotation. While in maintenance, the
+	// GRACEFUL_SHUTDOWN community (65535:0) is attached to all the advertisements,
+	// so that the neighbors can move the traffic away from the node.
+	// +optional
+	Maintenance *Maintenance `json:"maintenance,omitempty"`
+}
+
+// Maintenance represents the changes applied to the advertisements of a node
+// in maintenance, on top of the GRACEFUL_SHUTDOWN community.
+type Maintenance struct {
+	// ASPathPrependCount is the number of times the ASN of the router is prepended
+	// to the AS path of the advertisements. It replaces the prepends configured
+	// for the advertised prefixes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	ASPathPrependCount uint32 `json:"asPathPrependCount,omitempty"`
+	// LocalPref is the local preference set on the advertisements. It is
+	// meaningful only for iBGP neighbors.
+	// +optional
+	LocalPref uint32 `json:"localPref,omitempty"`
+	// WithdrawAfter is the time after which the prefixes are withdrawn from all
+	// the neighbors, starting from when the node entered maintenance. When not
+	// set, the prefixes are advertised until the maintenance ends.
+	// +optional
+	WithdrawAfter *metav1.Duration `json:"withdrawAfter,omitempty"`
+}
+
+// OSPFConfig is the configuration of the OSPF routers.
+type OSPFConfig struct {
+	// The list of OSPF routers we want FRR to configure (one per VRF).
+	// +optional
+	Routers []OSPFRouter `json:"routers,omitempty"`
+}
+
+// OSPFRouter represents an OSPFv2 router. As OSPFv2 is limited to IPv4,
+// only IPv4 prefixes can be redistributed.
+type OSPFRouter struct {
+	// OSPF router ID.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// The host VRF the router runs in.
+	// +optional
+	VRF string `json:"vrf,omitempty"`
+	// The list of areas the router's interfaces belong to.
+	// +optional
+	Areas []OSPFArea `json:"areas,omitempty"`
+	// The list of prefixes redistributed as external routes. The prefixes
+	// are redistributed only when they are in the routing table as connected,
+	// kernel or static routes.
+	// +optional
+	Prefixes []string `json:"prefixes,omitempty"`
+}
+
+// OSPFArea represents an OSPF area and the interfaces belonging to it.
+type OSPFArea struct {
+	// ID of the area, either in the dotted decimal (0.0.0.0) or in
+	// the decimal (0) form.
+	ID string `json:"id"`
+	// The list of interfaces belonging to the area.
+	// +optional
+	Interfaces []OSPFInterface `json:"interfaces,omitempty"`
+}
+
+// OSPFInterface represents an interface OSPF runs on.
+type OSPFInterface struct {
+	// Name of the interface.
+	Name string `json:"name"`
+	// Cost of the interface. If not set, FRR derives it from the bandwidth
+	// of the interface.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Cost uint32 `json:"cost,omitempty"`
+	// Passive interfaces don't establish adjacencies, but their networks
+	// are advertised.
+	// +optional
+	Passive bool `json:"passive,omitempty"`
+	// Authentication enables the MD5 authentication of the packets exchanged
+	// on the interface.
+	// +optional
+	Authentication *OSPFAuthentication `json:"authentication,omitempty"`
+}
+
+// OSPFAuthentication represents the MD5 authentication of an OSPF interface.
+type OSPFAuthentication struct {
+	// KeyID is the ID of the key, which must match the one of the neighbors.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=255
+	KeyID uint32 `json:"keyID"`
+	// PasswordSecret is a reference to a secret of type kubernetes.io/basic-auth
+	// in the frr-k8s namespace, whose password is used as key. The key can
+	// be up to 16 characters long.
+	PasswordSecret v1.SecretReference `json:"password"`
+}
+
+// StaticRoutes represents the static routes of a VRF.
+type StaticRoutes struct {
+	// The host VRF the routes are installed in.
+	// +optional
+	VRF string `json:"vrf,omitempty"`
+	// The list of routes. A prefix can be routed only once per VRF.
+	// +optional
+	Routes []StaticRoute `json:"routes,omitempty"`
+}
+
+// StaticRoute represents a static route towards a next hop, an interface
+// or, when blackhole is set, towards no destination.
+type StaticRoute struct {
+	// Prefix is the destination of the route, either IPv4 or IPv6.
+	Prefix string `json:"prefix"`
+	// NextHop is the address of the next hop, of the same family as the prefix.
+	// +optional
+	NextHop string `json:"nextHop,omitempty"`
+	// Interface is the name of the interface the route goes through.
+	// When set together with the next hop, the next hop is reached
+	// through the interface.
+	// +optional
+	Interface string `json:"interface,omitempty"`
+	// Distance is the administrative distance of the route.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=255
+	// +optional
+	Distance uint32 `json:"distance,omitempty"`
+	// Blackhole makes the route discard the traffic. A blackhole route
+	// can't have a next hop nor an interface.
+	// +optional
+	Blackhole bool `json:"blackhole,omitempty"`
+	// BFDProfile is the name of the BFD profile used to monitor the next hop.
+	// The route is withdrawn when the BFD session with the next hop goes down.
+	// The profile can be defined in any configuration.
+	// +optional
+	BFDProfile string `json:"bfdProfile,omitempty"`
 }
 
 // Router represent a neighbor router we want FRR to connect to.
+// Some of its fields accept node templates, ${...} expressions that each node
+// replaces with its own values: ${node.internalIPv4}, ${node.internalIPv6},
+// ${node.labels['key']}, ${node.annotations['key']} and ${node.podCIDRs}.
 type Router struct {
 	// AS number to use for the local end of the session.
+	// ASN and ASNTemplate are mutually exclusive.
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
 	ASN uint32 `json:"asn"`
-	// BGP router ID
+	// ASNTemplate is the AS number to use for the local end of the session,
+	// expressed as a node template resolved by each node (i.e. ${node.labels['asn']}).
+	// ASN and ASNTemplate are mutually exclusive.
+	// +optional
+	ASNTemplate string `json:"asnTemplate,omitempty"`
+	// BGP router ID. It may contain node templates, such as ${node.internalIPv4}.
 	// +optional
 	ID string `json:"id,omitempty"`
 	// The host VRF used to establish sessions from this router.
@@ -71,19 +228,206 @@ type Router struct {
 	// The list of neighbors we want to establish BGP sessions with.
 	// +optional
 	Neighbors []Neighbor `json:"neighbors,omitempty"`
+	// The list of dynamic neighbors, accepting sessions initiated by any
+	// peer whose address belongs to the given listen range.
+	// +optional
+	DynamicNeighbors []DynamicNeighbor `json:"dynamicNeighbors,omitempty"`
 	// The list of prefixes we want to advertise from this router instance.
+	// The prefixes may contain node templates, and ${node.podCIDRs} expands
+	// to all the pod CIDRs of the node.
 	// +optional
 	Prefixes []string `json:"prefixes,omitempty"`
+	// AdvertisePodCIDRs adds the pod CIDRs of the node (spec.podCIDRs) to the
+	// prefixes of the router. As the other prefixes, they are advertised to the
+	// neighbors according to their toAdvertise configuration. A node with no
+	// pod CIDRs assigned has no additional prefix to advertise.
+	// +optional
+	AdvertisePodCIDRs bool `json:"advertisePodCIDRs,omitempty"`
+	// GracefulRestart configures the graceful restart of the router's sessions,
+	// so that the routes are preserved while FRR restarts.
+	// +optional
+	GracefulRestart *GracefulRestart `json:"gracefulRestart,omitempty"`
+	// Imports is the list of VRFs whose routes are leaked into the router's VRF.
+	// +optional
+	Imports []Import `json:"imports,omitempty"`
+	// VPN configures the leaking of routes between VRFs through the VPN
+	// table, based on route targets.
+	// +optional
+	VPN *VPNLeak `json:"vpn,omitempty"`
+	// EVPN configures the l2vpn evpn address family of the router.
+	// +optional
+	EVPN *EVPN `json:"evpn,omitempty"`
+	// Redistribute configures the routes redistributed into BGP from the
+	// other sources, on top of the ones originated from Prefixes.
+	// +optional
+	Redistribute Redistribute `json:"redistribute,omitempty"`
+}
+
+// Redistribute holds the routes redistributed into BGP, per address family.
+type Redistribute struct {
+	// +optional
+	IPv4 []Redistribution `json:"ipv4,omitempty"`
+	// +optional
+	IPv6 []Redistribution `json:"ipv6,omitempty"`
+}
+
+// Redistribution represents the routes of a source redistributed into BGP.
+// The redistributed routes are advertised to the neighbors allowing all the
+// prefixes, and to the ones whose ranges or prefixes select them.
+type Redistribution struct {
+	// Source is the origin of the redistributed routes.
+	Source RedistributeSource `json:"source"`
+	// Table is the id of the kernel routing table the routes are taken from.
+	// It must be set only when the source is table.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Table uint32 `json:"table,omitempty"`
+	// Prefixes filters the redistributed routes, of the family of the
+	// redistribution. The ranges are evaluated in order, the first one matching
+	// a route decides if it is redistributed. When not set, all the routes
+	// are redistributed.
+	// +optional
+	Prefixes []PrefixRange `json:"prefixes,omitempty"`
+	// Communities are attached to the redistributed routes. Standard and
+	// large communities, and the community aliases are supported.
+	// +optional
+	Communities []string `json:"communities,omitempty"`
+	// LocalPref is the local preference set on the redistributed routes. It is
+	// meaningful only for iBGP neighbors.
+	// +optional
+	LocalPref uint32 `json:"localPref,omitempty"`
+}
+
+// EVPN configures the l2vpn evpn address family of a router. The route
+// distinguisher and the route targets are in the ASN:NN or IP:NN form.
+type EVPN struct {
+	// AdvertiseAllVNI advertises all the local VNIs. It can be set only on
+	// the router of the default VRF.
+	// +optional
+	AdvertiseAllVNI bool `json:"advertiseAllVNI,omitempty"`
+	// VNI is the L3VNI of the router's VRF. It can be set only on the routers
+	// of non default VRFs.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16777215
+	// +optional
+	VNI uint32 `json:"vni,omitempty"`
+	// RouteDistinguisher is the route distinguisher of the EVPN routes of the VRF.
+	// If not set, FRR derives it from the router ID.
+	// +optional
+	RouteDistinguisher string `json:"routeDistinguisher,omitempty"`
+	// ImportRouteTargets are the route targets of the EVPN routes imported
+	// into the VRF. If not set, FRR derives them from the VNI.
+	// +optional
+	ImportRouteTargets []string `json:"importRouteTargets,omitempty"`
+	// ExportRouteTargets are the route targets attached to the EVPN routes of
+	// the VRF. If not set, FRR derives them from the VNI.
+	// +optional
+	ExportRouteTargets []string `json:"exportRouteTargets,omitempty"`
+	// AdvertiseUnicast lists the unicast address families whose routes are
+	// advertised as EVPN type-5 routes.
+	// +optional
+	AdvertiseUnicast []EVPNUnicastFamily `json:"advertiseUnicast,omitempty"`
+}
+
+// Import represents the routes imported from another VRF.
+type Import struct {
+	// VRF is the VRF the routes are imported from. The default VRF is
+	// referenced as "default".
+	// +kubebuilder:validation:MinLength=1
+	VRF string `json:"vrf"`
+	// Prefixes restricts the imported routes to the ones permitted by the
+	// ranges, evaluated in order. When not set, all the routes are imported.
+	// +optional
+	Prefixes []PrefixRange `json:"prefixes,omitempty"`
+}
+
+// VPNLeak configures the export of the router's routes to the VPN table, and
+// the import of the VPN routes into the router's VRF. The route distinguisher
+// and the route targets are in the ASN:NN or IP:NN form.
+type VPNLeak struct {
+	// RouteDistinguisher is the route distinguisher of the exported routes.
+	// It is required when exporting routes.
+	// +optional
+	RouteDistinguisher string `json:"routeDistinguisher,omitempty"`
+	// ImportRouteTargets are the route targets of the VPN routes imported
+	// into the router's VRF.
+	// +optional
+	ImportRouteTargets []string `json:"importRouteTargets,omitempty"`
+	// ExportRouteTargets are the route targets attached to the routes
+	// exported to the VPN table.
+	// +optional
+	ExportRouteTargets []string `json:"exportRouteTargets,omitempty"`
+}
+
+// GracefulRestart configures BGP graceful restart (RFC 4724) and long-lived
+// graceful restart (RFC 9494) for a router.
+type GracefulRestart struct {
+	// Mode is the graceful restart mode of the router. With "restart", the router
+	// preserves its forwarding state across restarts and helps the restarting neighbors.
+	// With "helper", the router only helps the restarting neighbors, which is FRR's default.
+	// With "disabled", graceful restart is disabled.
+	// +kubebuilder:validation:Enum=restart;helper;disabled
+	// +kubebuilder:default:=helper
+	// +optional
+	Mode GracefulRestartMode `json:"mode,omitempty"`
+
+	// RestartTime is the time the neighbors wait for the sessions to be
+	// re-established after a restart. If not set, FRR's default of 120s is used.
+	// +optional
+	RestartTime metav1.Duration `json:"restartTime,omitempty"`
+
+	// StalePathTime is the maximum time the routes of a restarting neighbor
+	// are retained. If not set, FRR's default of 360s is used.
+	// +optional
+	StalePathTime metav1.Duration `json:"stalePathTime,omitempty"`
+
+	// LongLivedStaleTime enables the long-lived graceful restart, retaining
+	// the routes of a neighbor for the given time after the graceful restart
+	// expires.
+	// +optional
+	LongLivedStaleTime metav1.Duration `json:"longLivedStaleTime,omitempty"`
 }
 
 type Neighbor struct {
 	// AS number to use for the local end of the session.
+	// ASN and DynamicASN are mutually exclusive and one of them must be specified.
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=4294967295
-	ASN uint32 `json:"asn"`
+	// +optional
+	ASN uint32 `json:"asn,omitempty"`
 
-	// The IP address to establish the session with.
-	Address string `json:"address"`
+	// DynamicASN detects the AS number to use for the remote end of the session
+	// without explicitly setting it via the ASN field. Limited to:
+	// internal - if the neighbor's ASN is different than the router's the connection is denied.
+	// external - if the neighbor's ASN is the same as the router's the connection is denied.
+	// ASN and DynamicASN are mutually exclusive and one of them must be specified.
+	// +kubebuilder:validation:Enum=internal;external
+	// +optional
+	DynamicASN DynamicASNMode `json:"dynamicASN,omitempty"`
+
+	// The IP address to establish the session with. It may contain node templates,
+	// such as ${node.labels['tor-address']}.
+	// Address and Interface are mutually exclusive and one of them must be specified.
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// Interface is the node interface over which the unnumbered BGP peering will
+	// be established. The session is established over the IPv6 link local address
+	// of the interface, and carries both the IPv4 and IPv6 prefixes (RFC 5549).
+	// Address and Interface are mutually exclusive and one of them must be specified.
+	// +optional
+	Interface string `json:"interface,omitempty"`
+
+	// SourceAddress is the source address to use when establishing the session
+	// with the neighbor. It must belong to the same family of the neighbor's address.
+	// +optional
+	SourceAddress string `json:"sourceAddress,omitempty"`
+
+	// SourceAddressFrom selects the source address from the node the session is
+	// established from. It is mutually exclusive with SourceAddress.
+	// +optional
+	SourceAddressFrom *SourceAddressSelector `json:"sourceAddressFrom,omitempty"`
 
 	// Port to dial when establishing the session.
 	// +optional
@@ -111,11 +455,34 @@ type Neighbor struct {
 	// +optional
 	EBGPMultiHop bool `json:"ebgpMultiHop,omitempty"`
 
+	// To activate the l2vpn evpn address family for the neighbor.
+	// +optional
+	ActivateEVPN bool `json:"activateEVPN,omitempty"`
+
 	// The name of the BFD Profile to be used for the BFD session associated
 	// to the BGP session. If not set, the BFD session won't be set up.
 	// +optional
 	BFDProfile string `json:"bfdProfile,omitempty"`
 
+	// MaxPrefixes limits the number of prefixes received from the neighbor,
+	// per address family.
+	// +optional
+	MaxPrefixes MaxPrefixes `json:"maxPrefixes,omitempty"`
+
+	// GracefulRestart overrides the graceful restart mode of the router
+	// for the session.
+	// +kubebuilder:validation:Enum=restart;helper;disabled
+	// +optional
+	GracefulRestart GracefulRestartMode `json:"gracefulRestart,omitempty"`
+
+	// Template is the name of the neighbor template to inherit the properties
+	// not set in the neighbor from. The policies of the template are added to
+	// the neighbor's ones.
+	// Once a neighbor is bound to a template, the same neighbor declared in
+	// the other configurations inherits from the template too.
+	// +optional
+	Template string `json:"template,omitempty"`
+
 	// ToAdvertise represents the list of prefixes to advertise to the given neighbor
 	// and the associated properties.
 	// +optional
@@ -126,6 +493,189 @@ type Neighbor struct {
 	ToReceive Receive `json:"toReceive,omitempty"`
 }
 
+// NeighborTemplate holds the properties shared by multiple neighbors.
+type NeighborTemplate struct {
+	// The name of the template to be referenced by the neighbors.
+	Name string `json:"name"`
+
+	// passwordSecret is name of the authentication secret for the neighbors,
+	// with the same format of the neighbors' one.
+	// +optional
+	PasswordSecret v1.SecretReference `json:"password,omitempty"`
+
+	// Requested BGP hold time, per RFC4271.
+	// +optional
+	HoldTime metav1.Duration `json:"holdTime,omitempty"`
+
+	// Requested BGP keepalive time, per RFC4271.
+	// +optional
+	KeepaliveTime metav1.Duration `json:"keepaliveTime,omitempty"`
+
+	// To set if the neighbors are multi-hops away.
+	// +optional
+	EBGPMultiHop bool `json:"ebgpMultiHop,omitempty"`
+
+	// To activate the l2vpn evpn address family for the neighbors.
+	// +optional
+	ActivateEVPN bool `json:"activateEVPN,omitempty"`
+
+	// The name of the BFD Profile to be used for the BFD sessions associated
+	// to the BGP sessions.
+	// +optional
+	BFDProfile string `json:"bfdProfile,omitempty"`
+
+	// MaxPrefixes limits the number of prefixes received from the neighbors,
+	// per address family.
+	// +optional
+	MaxPrefixes MaxPrefixes `json:"maxPrefixes,omitempty"`
+
+	// GracefulRestart overrides the graceful restart mode of the router
+	// for the sessions.
+	// +kubebuilder:validation:Enum=restart;helper;disabled
+	// +optional
+	GracefulRestart GracefulRestartMode `json:"gracefulRestart,omitempty"`
+
+	// ToAdvertise represents the list of prefixes to advertise to the neighbors
+	// and the associated properties.
+	// +optional
+	ToAdvertise Advertise `json:"toAdvertise,omitempty"`
+
+	// Receive represents the list of prefixes to receive from the neighbors.
+	// +optional
+	ToReceive Receive `json:"toReceive,omitempty"`
+}
+
+// DynamicNeighbor represents a group of neighbors that are not known in advance,
+// and that are allowed to establish a session from any address of a given range.
+type DynamicNeighbor struct {
+	// ListenRange is the CIDR the sessions are accepted from.
+	// Ranges of different dynamic neighbors of the same router must not overlap.
+	// +kubebuilder:validation:Format="cidr"
+	ListenRange string `json:"listenRange"`
+
+	// AS number of the neighbors in the listen range.
+	// ASN and DynamicASN are mutually exclusive and one of them must be specified.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
+	ASN uint32 `json:"asn,omitempty"`
+
+	// DynamicASN detects the AS number of the neighbors in the listen range,
+	// as for the neighbors' DynamicASN.
+	// ASN and DynamicASN are mutually exclusive and one of them must be specified.
+	// +kubebuilder:validation:Enum=internal;external
+	// +optional
+	DynamicASN DynamicASNMode `json:"dynamicASN,omitempty"`
+
+	// SessionLimit is the maximum number of sessions accepted from the listen range.
+	// FRR enforces the limit on the whole router, so the router accepts up to the sum
+	// of the limits of its dynamic neighbors. If not set, FRR's default of 100
+	// sessions is used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	SessionLimit uint32 `json:"sessionLimit,omitempty"`
+
+	// passwordSecret is name of the authentication secret for the neighbors,
+	// with the same format of the neighbors' one.
+	// +optional
+	PasswordSecret v1.SecretReference `json:"password,omitempty"`
+
+	// Requested BGP hold time, per RFC4271.
+	// +optional
+	HoldTime metav1.Duration `json:"holdTime,omitempty"`
+
+	// Requested BGP keepalive time, per RFC4271.
+	// +optional
+	KeepaliveTime metav1.Duration `json:"keepaliveTime,omitempty"`
+
+	// To set if the neighbors are multi-hops away.
+	// +optional
+	EBGPMultiHop bool `json:"ebgpMultiHop,omitempty"`
+
+	// To activate the l2vpn evpn address family for the neighbors.
+	// +optional
+	ActivateEVPN bool `json:"activateEVPN,omitempty"`
+
+	// The name of the BFD Profile to be used for the BFD sessions associated
+	// to the BGP sessions. If not set, the BFD sessions won't be set up.
+	// +optional
+	BFDProfile string `json:"bfdProfile,omitempty"`
+
+	// MaxPrefixes limits the number of prefixes received from each of the
+	// neighbors, per address family.
+	// +optional
+	MaxPrefixes MaxPrefixes `json:"maxPrefixes,omitempty"`
+
+	// GracefulRestart overrides the graceful restart mode of the router
+	// for the sessions.
+	// +kubebuilder:validation:Enum=restart;helper;disabled
+	// +optional
+	GracefulRestart GracefulRestartMode `json:"gracefulRestart,omitempty"`
+
+	// ToAdvertise represents the list of prefixes to advertise to the neighbors
+	// and the associated properties.
+	// +optional
+	ToAdvertise Advertise `json:"toAdvertise,omitempty"`
+
+	// Receive represents the list of prefixes to receive from the neighbors.
+	// +optional
+	ToReceive Receive `json:"toReceive,omitempty"`
+}
+
+// MaxPrefixes limits the number of prefixes received from a neighbor, per address family.
+type MaxPrefixes struct {
+	// IPv4 is the limit for the IPv4 unicast address family.
+	// +optional
+	IPv4 *PrefixLimit `json:"ipv4,omitempty"`
+
+	// IPv6 is the limit for the IPv6 unicast address family.
+	// +optional
+	IPv6 *PrefixLimit `json:"ipv6,omitempty"`
+}
+
+// PrefixLimit is the maximum number of prefixes received from a neighbor for
+// an address family. When the limit is exceeded, the session is torn down
+// unless WarningOnly is set.
+type PrefixLimit struct {
+	// Limit is the maximum number of prefixes.
+	// +kubebuilder:validation:Minimum=1
+	Limit uint32 `json:"limit"`
+
+	// ThresholdPercentage is the percentage of the limit at which a warning
+	// is logged. If not set, FRR's default of 75% is used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ThresholdPercentage uint32 `json:"thresholdPercentage,omitempty"`
+
+	// WarningOnly only logs a warning when the limit is exceeded, instead of
+	// tearing the session down.
+	// WarningOnly and RestartInterval are mutually exclusive.
+	// +optional
+	WarningOnly bool `json:"warningOnly,omitempty"`
+
+	// RestartInterval is the time after which a session torn down for exceeding
+	// the limit is restarted, in whole minutes. If not set, the session is not
+	// restarted automatically.
+	// +optional
+	RestartInterval metav1.Duration `json:"restartInterval,omitempty"`
+}
+
+// SourceAddressSelector selects the source address of a session from the
+// node the session is established from. Exactly one of the fields must be set.
+type SourceAddressSelector struct {
+	// NodeInternalIP, when true, selects the node's InternalIP matching the
+	// family of the neighbor's address.
+	// +optional
+	NodeInternalIP bool `json:"nodeInternalIP,omitempty"`
+
+	// Interface selects the first global unicast address of the given local
+	// interface matching the family of the neighbor's address.
+	// +optional
+	Interface string `json:"interface,omitempty"`
+}
+
 type Advertise struct {
 	// Prefixes is the list of prefixes allowed to be propagated to
 	// this neighbor. They must match the prefixes defined in the router.
@@ -142,6 +692,18 @@ type Advertise struct {
 	// must be in the prefixes allowed to be advertised.
 	// +optional
 	PrefixesWithCommunity []CommunityPrefixes `json:"withCommunity,omitempty"`
+
+	// PrefixesWithASPathPrepend is a list of prefixes whose AS path is prepended
+	// when being advertised. The prefixes associated to a given prepend
+	// must be in the prefixes allowed to be advertised.
+	// +optional
+	PrefixesWithASPathPrepend []ASPathPrependPrefixes `json:"withASPathPrepend,omitempty"`
+
+	// PrefixesWithMED is a list of prefixes that are associated to a
+	// multi exit discriminator when being advertised. The prefixes associated
+	// to a given MED must be in the prefixes allowed to be advertised.
+	// +optional
+	PrefixesWithMED []MEDPrefixes `json:"withMED,omitempty"`
 }
 
 type Receive struct {
@@ -149,11 +711,62 @@ type Receive struct {
 	// this neighbor.
 	// +optional
 	Allowed AllowedPrefixes `json:"allowed,omitempty"`
+
+	// Policies is a list of actions to apply to the received routes.
+	// The policies are applied in order, and apply only to the routes
+	// allowed to be received.
+	// +optional
+	Policies []ReceivePolicy `json:"policies,omitempty"`
+}
+
+// ReceivePolicy applies a set of actions to the received routes matching it.
+// A route matches the policy when it matches both the prefixes and the communities,
+// if specified. A policy with no prefixes and no communities matches all the routes.
+type ReceivePolicy struct {
+	// Prefixes is the list of prefixes the policy applies to.
+	// +kubebuilder:validation:Format="cidr"
+	// +optional
+	Prefixes []string `json:"prefixes,omitempty"`
+
+	// Communities makes the policy apply to the routes carrying any of the
+	// given communities. Standard and large communities, well-known community
+	// names and CommunityAlias names are accepted.
+	// +optional
+	Communities []string `json:"communities,omitempty"`
+
+	// LocalPref is the local preference to set on the matching routes.
+	// +optional
+	LocalPref *uint32 `json:"localPref,omitempty"`
+
+	// Weight is the weight to set on the matching routes.
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Weight *uint32 `json:"weight,omitempty"`
+
+	// SetCommunities is the list of communities to add to the matching routes.
+	// When StripCommunities is set, they replace the existing ones.
+	// +optional
+	SetCommunities []string `json:"setCommunities,omitempty"`
+
+	// StripCommunities removes the standard and large communities from the
+	// matching routes.
+	// +optional
+	StripCommunities bool `json:"stripCommunities,omitempty"`
 }
 
 type AllowedPrefixes struct {
 	// +kubebuilder:validation:Format="cidr"
 	Prefixes []string `json:"prefixes,omitempty"`
+	// Ranges is a list of prefix ranges, permitting or denying the prefixes
+	// matching them. The ranges are evaluated in order and before Prefixes,
+	// the first one matching a prefix decides if it is allowed.
+	// When multiple configurations specify ranges for the same neighbor, they
+	// are evaluated in the order of the configurations, and the ranges of the
+	// older configurations take precedence.
+	// When advertising, the ranges select the prefixes of the router and the
+	// redistributed routes they match.
+	// +optional
+	Ranges []PrefixRange `json:"ranges,omitempty"`
 	// Mode is the mode to use when handling the prefixes.
 	// When set to "filtered", only the prefixes in the given list will be allowed.
 	// When set to "all", all the prefixes configured on the router will be allowed.
@@ -161,6 +774,27 @@ type AllowedPrefixes struct {
 	Mode AllowMode `json:"mode,omitempty"`
 }
 
+// PrefixRange matches the prefixes contained in the given one, with a length
+// within the given bounds. With no bounds, only the given prefix is matched.
+type PrefixRange struct {
+	// +kubebuilder:validation:Format="cidr"
+	Prefix string `json:"prefix"`
+	// GE is the minimum length of the matched prefixes. It must be greater
+	// than the length of Prefix.
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	GE uint32 `json:"ge,omitempty"`
+	// LE is the maximum length of the matched prefixes. It must be greater
+	// than the length of Prefix, and not lower than GE.
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	LE uint32 `json:"le,omitempty"`
+	// Action tells if the matched prefixes are permitted or denied.
+	// +kubebuilder:default:=permit
+	// +optional
+	Action PrefixAction `json:"action,omitempty"`
+}
+
 type LocalPrefPrefixes struct {
 	// Prefixes is the list of prefixes associated to the local preference.
 	// +kubebuilder:validation:MinItems=1
@@ -169,12 +803,48 @@ type LocalPrefPrefixes struct {
 	LocalPref uint32   `json:"localPref,omitempty"`
 }
 
+type ASPathPrependPrefixes struct {
+	// Prefixes is the list of prefixes associated to the AS path prepend.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Format="cidr"
+	Prefixes []string `json:"prefixes,omitempty"`
+	// ASN is the AS number to prepend to the AS path. ASN and LastAS are
+	// mutually exclusive and one of them must be specified.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
+	ASN uint32 `json:"asn,omitempty"`
+	// LastAS, when true, prepends the last AS number of the AS path.
+	// +optional
+	LastAS bool `json:"lastAS,omitempty"`
+	// Count is the number of times the AS number is prepended.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	Count uint32 `json:"count"`
+}
+
+type MEDPrefixes struct {
+	// Prefixes is the list of prefixes associated to the MED.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Format="cidr"
+	Prefixes []string `json:"prefixes,omitempty"`
+	// MED is the multi exit discriminator to set on the prefixes.
+	MED uint32 `json:"med"`
+}
+
 type CommunityPrefixes struct {
 	// Prefixes is the list of prefixes associated to the community.
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:Format="cidr"
-	Prefixes  []string `json:"prefixes,omitempty"`
-	Community string   `json:"community,omitempty"`
+	Prefixes []string `json:"prefixes,omitempty"`
+	// Community is the community to associate to the prefixes. It can be a legacy
+	// community in the "<AS number>:<community value>" form, a large community in the
+	// "large:<global administrator>:<localdata part 1>:<localdata part 2>" form or an
+	// extended community in the "<type>:<administrator>:<assigned-number>" form, where
+	// type is one of rt (route target), soo (site of origin) and lb (link bandwidth,
+	// with the administrator being the AS number of the router and the assigned
+	// number the bandwidth in Mbps).
+	Community string `json:"community,omitempty"`
 }
 
 type BFDProfile struct {
@@ -240,11 +910,45 @@ type BFDProfile struct {
 }
 
 // FRRConfigurationStatus defines the observed state of FRRConfiguration.
+// It aggregates the outcome across the nodes selecting the configuration,
+// the per node details are exposed by the FRRNodeState of each node.
 type FRRConfigurationStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Conditions represent the latest available observations of the
+	// configuration, aggregated across the nodes.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// FailedNodes is the list of the nodes where the configuration could not
+	// be applied. Only the failures are tracked, so that the nodes applying
+	// the configuration successfully don't need to update it.
+	// +optional
+	// +listType=set
+	FailedNodes []string `json:"failedNodes,omitempty"`
 }
 
+const (
+	// ConditionApplied is set to true when the configuration was translated,
+	// merged with the others selected by the node and handed to FRR on all
+	// the nodes selecting it.
+	ConditionApplied = "Applied"
+
+	ReasonConfigApplied    = "ConfigApplied"
+	ReasonConversionFailed = "ConversionFailed"
+	ReasonApplyFailed      = "ApplyFailed"
+	// ReasonConfigExcluded is used when the configuration is invalid or conflicts
+	// with the others selected by the node, and is left out of the node's config.
+	ReasonConfigExcluded = "ConfigExcluded"
+	// ReasonFailedOnNodes is used when the configuration could not be applied
+	// on some of the nodes selecting it.
+	ReasonFailedOnNodes = "FailedOnNodes"
+)
+
+// MaintenanceAnnotation is the annotation putting the node it is set on in
+// maintenance, regardless of its value.
+const MaintenanceAnnotation = "frrk8s.metallb.io/maintenance"
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
@@ -270,6 +974,29 @@ func init() {
 	SchemeBuilder.Register(&FRRConfiguration{}, &FRRConfigurationList{})
 }
 
+type DynamicASNMode string
+
+const (
+	InternalASNMode DynamicASNMode = "internal"
+	ExternalASNMode DynamicASNMode = "external"
+)
+
+type GracefulRestartMode string
+
+const (
+	GracefulRestartRestart  GracefulRestartMode = "restart"
+	GracefulRestartHelper   GracefulRestartMode = "helper"
+	GracefulRestartDisabled GracefulRestartMode = "disabled"
+)
+
+// +kubebuilder:validation:Enum=permit;deny
+type PrefixAction string
+
+const (
+	PermitPrefix PrefixAction = "permit"
+	DenyPrefix   PrefixAction = "deny"
+)
+
 // +kubebuilder:validation:Enum=all;filtered
 type AllowMode string
 
@@ -277,3 +1004,21 @@ const (
 	AllowAll        AllowMode = "all"
 	AllowRestricted AllowMode = "filtered"
 )
+
+// +kubebuilder:validation:Enum=ipv4;ipv6
+type EVPNUnicastFamily string
+
+const (
+	EVPNUnicastIPv4 EVPNUnicastFamily = "ipv4"
+	EVPNUnicastIPv6 EVPNUnicastFamily = "ipv6"
+)
+
+// +kubebuilder:validation:Enum=connected;kernel;static;table
+type RedistributeSource string
+
+const (
+	RedistributeConnected RedistributeSource = "connected"
+	RedistributeKernel    RedistributeSource = "kernel"
+	RedistributeStatic    RedistributeSource = "static"
+	RedistributeTable     RedistributeSource = "table"
+)
@@ -0,0 +1,67 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// FRRConfigurationValidator validates a FRRConfiguration before it is stored.
+// +kubebuilder:object:generate=false
+type FRRConfigurationValidator interface {
+	Validate(config *FRRConfiguration) error
+}
+
+// Validator is the validator used by the FRRConfiguration webhook. It must be
+// set before registering the webhook with the manager.
+var Validator FRRConfigurationValidator
+
+func (frrConfig *FRRConfiguration) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(frrConfig).
+		Complete()
+}
+
+//+kubebuilder:webhook:verbs=create;update,path=/validate-frrk8s-metallb-io-v1beta1-frrconfiguration,mutating=false,failurePolicy=fail,groups=frrk8s.metallb.io,resources=frrconfigurations,versions=v1beta1,name=frrconfigurationsvalidationwebhook.metallb.io,sideEffects=None,admissionReviewVersions=v1
+
+var _ webhook.Validator = &FRRConfiguration{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (frrConfig *FRRConfiguration) ValidateCreate() error {
+	return validate(frrConfig)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (frrConfig *FRRConfiguration) ValidateUpdate(old runtime.Object) error {
+	return validate(frrConfig)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (frrConfig *FRRConfiguration) ValidateDelete() error {
+	return nil
+}
+
+func validate(frrConfig *FRRConfiguration) error {
+	if Validator == nil {
+		return fmt.Errorf("no validator set for FRRConfiguration")
+	}
+	return Validator.Validate(frrConfig)
+}
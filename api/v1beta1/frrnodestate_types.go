@@ -0,0 +1,96 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FRRNodeStateSpec defines the desired state of FRRNodeState.
+type FRRNodeStateSpec struct {
+}
+
+// FRRNodeStateStatus defines the observed state of FRRNodeState.
+type FRRNodeStateStatus struct {
+	// RunningConfig represents the last FRR configuration rendered and
+	// applied on the node, with the passwords redacted.
+	// +optional
+	RunningConfig string `json:"runningConfig,omitempty"`
+
+	// SelectedConfigurations is the list of the FRRConfigurations (in the
+	// namespace/name form) selected by the node and merged into the
+	// running config.
+	// +optional
+	SelectedConfigurations []string `json:"selectedConfigurations,omitempty"`
+
+	// LastReloadResult represents the result of the last FRR reload,
+	// as reported by the reloader.
+	// +optional
+	LastReloadResult string `json:"lastReloadResult,omitempty"`
+
+	// LastConversionResult represents the result of the last translation
+	// and merge of the selected FRRConfigurations. It is "success" when the
+	// conversion succeeded, and contains the error otherwise.
+	// +optional
+	LastConversionResult string `json:"lastConversionResult,omitempty"`
+
+	// FailedConfigurations is the list of the FRRConfigurations selected by the
+	// node that could not be applied, with the reason of the failure.
+	// +optional
+	FailedConfigurations []FailedConfiguration `json:"failedConfigurations,omitempty"`
+}
+
+// FailedConfiguration describes why an FRRConfiguration could not be applied
+// on the node.
+type FailedConfiguration struct {
+	// Name is the FRRConfiguration, in the namespace/name form.
+	Name string `json:"name"`
+	// Reason is one of ConversionFailed, ApplyFailed or ConfigExcluded.
+	Reason string `json:"reason"`
+	// Message details the failure.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Reload",type=string,JSONPath=`.status.lastReloadResult`
+//+kubebuilder:printcolumn:name="Conversion",type=string,JSONPath=`.status.lastConversionResult`
+
+// FRRNodeState exposes the status of the FRR instance running on a node.
+// The object is named after the node it refers to.
+type FRRNodeState struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FRRNodeStateSpec   `json:"spec,omitempty"`
+	Status FRRNodeStateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FRRNodeStateList contains a list of FRRNodeState.
+type FRRNodeStateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FRRNodeState `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FRRNodeState{}, &FRRNodeStateList{})
+}
@@ -22,9 +22,30 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ASPathPrependPrefixes) DeepCopyInto(out *ASPathPrependPrefixes) {
+	*out = *in
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ASPathPrependPrefixes.
+func (in *ASPathPrependPrefixes) DeepCopy() *ASPathPrependPrefixes {
+	if in == nil {
+		return nil
+	}
+	out := new(ASPathPrependPrefixes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Advertise) DeepCopyInto(out *Advertise) {
 	*out = *in
@@ -43,6 +64,20 @@ func (in *Advertise) DeepCopyInto(out *Advertise) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PrefixesWithASPathPrepend != nil {
+		in, out := &in.PrefixesWithASPathPrepend, &out.PrefixesWithASPathPrepend
+		*out = make([]ASPathPrependPrefixes, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrefixesWithMED != nil {
+		in, out := &in.PrefixesWithMED, &out.PrefixesWithMED
+		*out = make([]MEDPrefixes, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Advertise.
@@ -63,6 +98,11 @@ func (in *AllowedPrefixes) DeepCopyInto(out *AllowedPrefixes) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Ranges != nil {
+		in, out := &in.Ranges, &out.Ranges
+		*out = make([]PrefixRange, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedPrefixes.
@@ -105,6 +145,18 @@ func (in *BGPConfig) DeepCopyInto(out *BGPConfig) {
 		*out = make([]BFDProfile, len(*in))
 		copy(*out, *in)
 	}
+	if in.NeighborTemplates != nil {
+		in, out := &in.NeighborTemplates, &out.NeighborTemplates
+		*out = make([]NeighborTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(Maintenance)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPConfig.
@@ -117,6 +169,79 @@ func (in *BGPConfig) DeepCopy() *BGPConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommunityAlias) DeepCopyInto(out *CommunityAlias) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommunityAlias.
+func (in *CommunityAlias) DeepCopy() *CommunityAlias {
+	if in == nil {
+		return nil
+	}
+	out := new(CommunityAlias)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CommunityAlias) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommunityAliasList) DeepCopyInto(out *CommunityAliasList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CommunityAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommunityAliasList.
+func (in *CommunityAliasList) DeepCopy() *CommunityAliasList {
+	if in == nil {
+		return nil
+	}
+	out := new(CommunityAliasList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CommunityAliasList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommunityAliasSpec) DeepCopyInto(out *CommunityAliasSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommunityAliasSpec.
+func (in *CommunityAliasSpec) DeepCopy() *CommunityAliasSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CommunityAliasSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommunityPrefixes) DeepCopyInto(out *CommunityPrefixes) {
 	*out = *in
@@ -137,13 +262,64 @@ func (in *CommunityPrefixes) DeepCopy() *CommunityPrefixes {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicNeighbor) DeepCopyInto(out *DynamicNeighbor) {
+	*out = *in
+	out.PasswordSecret = in.PasswordSecret
+	out.HoldTime = in.HoldTime
+	out.KeepaliveTime = in.KeepaliveTime
+	in.MaxPrefixes.DeepCopyInto(&out.MaxPrefixes)
+	in.ToAdvertise.DeepCopyInto(&out.ToAdvertise)
+	in.ToReceive.DeepCopyInto(&out.ToReceive)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicNeighbor.
+func (in *DynamicNeighbor) DeepCopy() *DynamicNeighbor {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicNeighbor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EVPN) DeepCopyInto(out *EVPN) {
+	*out = *in
+	if in.ImportRouteTargets != nil {
+		in, out := &in.ImportRouteTargets, &out.ImportRouteTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExportRouteTargets != nil {
+		in, out := &in.ExportRouteTargets, &out.ExportRouteTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdvertiseUnicast != nil {
+		in, out := &in.AdvertiseUnicast, &out.AdvertiseUnicast
+		*out = make([]EVPNUnicastFamily, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EVPN.
+func (in *EVPN) DeepCopy() *EVPN {
+	if in == nil {
+		return nil
+	}
+	out := new(EVPN)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FRRConfiguration) DeepCopyInto(out *FRRConfiguration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FRRConfiguration.
@@ -200,6 +376,14 @@ func (in *FRRConfigurationList) DeepCopyObject() runtime.Object {
 func (in *FRRConfigurationSpec) DeepCopyInto(out *FRRConfigurationSpec) {
 	*out = *in
 	in.BGP.DeepCopyInto(&out.BGP)
+	in.OSPF.DeepCopyInto(&out.OSPF)
+	if in.StaticRoutes != nil {
+		in, out := &in.StaticRoutes, &out.StaticRoutes
+		*out = make([]StaticRoutes, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Raw.DeepCopyInto(&out.Raw)
 	in.NodeSelector.DeepCopyInto(&out.NodeSelector)
 }
@@ -217,6 +401,18 @@ func (in *FRRConfigurationSpec) DeepCopy() *FRRConfigurationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FRRConfigurationStatus) DeepCopyInto(out *FRRConfigurationStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailedNodes != nil {
+		in, out := &in.FailedNodes, &out.FailedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FRRConfigurationStatus.
@@ -230,91 +426,180 @@ func (in *FRRConfigurationStatus) DeepCopy() *FRRConfigurationStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalPrefPrefixes) DeepCopyInto(out *LocalPrefPrefixes) {
+func (in *FRRNodeState) DeepCopyInto(out *FRRNodeState) {
 	*out = *in
-	if in.Prefixes != nil {
-		in, out := &in.Prefixes, &out.Prefixes
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FRRNodeState.
+func (in *FRRNodeState) DeepCopy() *FRRNodeState {
+	if in == nil {
+		return nil
+	}
+	out := new(FRRNodeState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FRRNodeState) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FRRNodeStateList) DeepCopyInto(out *FRRNodeStateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FRRNodeState, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FRRNodeStateList.
+func (in *FRRNodeStateList) DeepCopy() *FRRNodeStateList {
+	if in == nil {
+		return nil
+	}
+	out := new(FRRNodeStateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FRRNodeStateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FRRNodeStateSpec) DeepCopyInto(out *FRRNodeStateSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FRRNodeStateSpec.
+func (in *FRRNodeStateSpec) DeepCopy() *FRRNodeStateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FRRNodeStateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FRRNodeStateStatus) DeepCopyInto(out *FRRNodeStateStatus) {
+	*out = *in
+	if in.SelectedConfigurations != nil {
+		in, out := &in.SelectedConfigurations, &out.SelectedConfigurations
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.FailedConfigurations != nil {
+		in, out := &in.FailedConfigurations, &out.FailedConfigurations
+		*out = make([]FailedConfiguration, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalPrefPrefixes.
-func (in *LocalPrefPrefixes) DeepCopy() *LocalPrefPrefixes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FRRNodeStateStatus.
+func (in *FRRNodeStateStatus) DeepCopy() *FRRNodeStateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalPrefPrefixes)
+	out := new(FRRNodeStateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Neighbor) DeepCopyInto(out *Neighbor) {
+func (in *FailedConfiguration) DeepCopyInto(out *FailedConfiguration) {
 	*out = *in
-	out.PasswordSecret = in.PasswordSecret
-	out.HoldTime = in.HoldTime
-	out.KeepaliveTime = in.KeepaliveTime
-	in.ToAdvertise.DeepCopyInto(&out.ToAdvertise)
-	in.ToReceive.DeepCopyInto(&out.ToReceive)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Neighbor.
-func (in *Neighbor) DeepCopy() *Neighbor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedConfiguration.
+func (in *FailedConfiguration) DeepCopy() *FailedConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(Neighbor)
+	out := new(FailedConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RawConfig) DeepCopyInto(out *RawConfig) {
+func (in *GracefulRestart) DeepCopyInto(out *GracefulRestart) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = make([]byte, len(*in))
+	out.RestartTime = in.RestartTime
+	out.StalePathTime = in.StalePathTime
+	out.LongLivedStaleTime = in.LongLivedStaleTime
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GracefulRestart.
+func (in *GracefulRestart) DeepCopy() *GracefulRestart {
+	if in == nil {
+		return nil
+	}
+	out := new(GracefulRestart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Import) DeepCopyInto(out *Import) {
+	*out = *in
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]PrefixRange, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RawConfig.
-func (in *RawConfig) DeepCopy() *RawConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Import.
+func (in *Import) DeepCopy() *Import {
 	if in == nil {
 		return nil
 	}
-	out := new(RawConfig)
+	out := new(Import)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Receive) DeepCopyInto(out *Receive) {
+func (in *LocalPrefPrefixes) DeepCopyInto(out *LocalPrefPrefixes) {
 	*out = *in
-	in.Allowed.DeepCopyInto(&out.Allowed)
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Receive.
-func (in *Receive) DeepCopy() *Receive {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalPrefPrefixes.
+func (in *LocalPrefPrefixes) DeepCopy() *LocalPrefPrefixes {
 	if in == nil {
 		return nil
 	}
-	out := new(Receive)
+	out := new(LocalPrefPrefixes)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Router) DeepCopyInto(out *Router) {
+func (in *MEDPrefixes) DeepCopyInto(out *MEDPrefixes) {
 	*out = *in
-	if in.Neighbors != nil {
-		in, out := &in.Neighbors, &out.Neighbors
-		*out = make([]Neighbor, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	if in.Prefixes != nil {
 		in, out := &in.Prefixes, &out.Prefixes
 		*out = make([]string, len(*in))
@@ -322,12 +607,511 @@ func (in *Router) DeepCopyInto(out *Router) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Router.
-func (in *Router) DeepCopy() *Router {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MEDPrefixes.
+func (in *MEDPrefixes) DeepCopy() *MEDPrefixes {
 	if in == nil {
 		return nil
 	}
-	out := new(Router)
+	out := new(MEDPrefixes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Maintenance) DeepCopyInto(out *Maintenance) {
+	*out = *in
+	if in.WithdrawAfter != nil {
+		in, out := &in.WithdrawAfter, &out.WithdrawAfter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Maintenance.
+func (in *Maintenance) DeepCopy() *Maintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(Maintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaxPrefixes) DeepCopyInto(out *MaxPrefixes) {
+	*out = *in
+	if in.IPv4 != nil {
+		in, out := &in.IPv4, &out.IPv4
+		*out = new(PrefixLimit)
+		**out = **in
+	}
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = new(PrefixLimit)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaxPrefixes.
+func (in *MaxPrefixes) DeepCopy() *MaxPrefixes {
+	if in == nil {
+		return nil
+	}
+	out := new(MaxPrefixes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Neighbor) DeepCopyInto(out *Neighbor) {
+	*out = *in
+	if in.SourceAddressFrom != nil {
+		in, out := &in.SourceAddressFrom, &out.SourceAddressFrom
+		*out = new(SourceAddressSelector)
+		**out = **in
+	}
+	out.PasswordSecret = in.PasswordSecret
+	out.HoldTime = in.HoldTime
+	out.KeepaliveTime = in.KeepaliveTime
+	in.MaxPrefixes.DeepCopyInto(&out.MaxPrefixes)
+	in.ToAdvertise.DeepCopyInto(&out.ToAdvertise)
+	in.ToReceive.DeepCopyInto(&out.ToReceive)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Neighbor.
+func (in *Neighbor) DeepCopy() *Neighbor {
+	if in == nil {
+		return nil
+	}
+	out := new(Neighbor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NeighborTemplate) DeepCopyInto(out *NeighborTemplate) {
+	*out = *in
+	out.PasswordSecret = in.PasswordSecret
+	out.HoldTime = in.HoldTime
+	out.KeepaliveTime = in.KeepaliveTime
+	in.MaxPrefixes.DeepCopyInto(&out.MaxPrefixes)
+	in.ToAdvertise.DeepCopyInto(&out.ToAdvertise)
+	in.ToReceive.DeepCopyInto(&out.ToReceive)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeighborTemplate.
+func (in *NeighborTemplate) DeepCopy() *NeighborTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NeighborTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSPFArea) DeepCopyInto(out *OSPFArea) {
+	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]OSPFInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSPFArea.
+func (in *OSPFArea) DeepCopy() *OSPFArea {
+	if in == nil {
+		return nil
+	}
+	out := new(OSPFArea)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSPFAuthentication) DeepCopyInto(out *OSPFAuthentication) {
+	*out = *in
+	out.PasswordSecret = in.PasswordSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSPFAuthentication.
+func (in *OSPFAuthentication) DeepCopy() *OSPFAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(OSPFAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSPFConfig) DeepCopyInto(out *OSPFConfig) {
+	*out = *in
+	if in.Routers != nil {
+		in, out := &in.Routers, &out.Routers
+		*out = make([]OSPFRouter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSPFConfig.
+func (in *OSPFConfig) DeepCopy() *OSPFConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OSPFConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSPFInterface) DeepCopyInto(out *OSPFInterface) {
+	*out = *in
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(OSPFAuthentication)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSPFInterface.
+func (in *OSPFInterface) DeepCopy() *OSPFInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(OSPFInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSPFRouter) DeepCopyInto(out *OSPFRouter) {
+	*out = *in
+	if in.Areas != nil {
+		in, out := &in.Areas, &out.Areas
+		*out = make([]OSPFArea, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSPFRouter.
+func (in *OSPFRouter) DeepCopy() *OSPFRouter {
+	if in == nil {
+		return nil
+	}
+	out := new(OSPFRouter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrefixLimit) DeepCopyInto(out *PrefixLimit) {
+	*out = *in
+	out.RestartInterval = in.RestartInterval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrefixLimit.
+func (in *PrefixLimit) DeepCopy() *PrefixLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(PrefixLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrefixRange) DeepCopyInto(out *PrefixRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrefixRange.
+func (in *PrefixRange) DeepCopy() *PrefixRange {
+	if in == nil {
+		return nil
+	}
+	out := new(PrefixRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RawConfig) DeepCopyInto(out *RawConfig) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RawConfig.
+func (in *RawConfig) DeepCopy() *RawConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RawConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Receive) DeepCopyInto(out *Receive) {
+	*out = *in
+	in.Allowed.DeepCopyInto(&out.Allowed)
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]ReceivePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Receive.
+func (in *Receive) DeepCopy() *Receive {
+	if in == nil {
+		return nil
+	}
+	out := new(Receive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReceivePolicy) DeepCopyInto(out *ReceivePolicy) {
+	*out = *in
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Communities != nil {
+		in, out := &in.Communities, &out.Communities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LocalPref != nil {
+		in, out := &in.LocalPref, &out.LocalPref
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.SetCommunities != nil {
+		in, out := &in.SetCommunities, &out.SetCommunities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceivePolicy.
+func (in *ReceivePolicy) DeepCopy() *ReceivePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ReceivePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redistribute) DeepCopyInto(out *Redistribute) {
+	*out = *in
+	if in.IPv4 != nil {
+		in, out := &in.IPv4, &out.IPv4
+		*out = make([]Redistribution, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = make([]Redistribution, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redistribute.
+func (in *Redistribute) DeepCopy() *Redistribute {
+	if in == nil {
+		return nil
+	}
+	out := new(Redistribute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redistribution) DeepCopyInto(out *Redistribution) {
+	*out = *in
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]PrefixRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Communities != nil {
+		in, out := &in.Communities, &out.Communities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redistribution.
+func (in *Redistribution) DeepCopy() *Redistribution {
+	if in == nil {
+		return nil
+	}
+	out := new(Redistribution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Router) DeepCopyInto(out *Router) {
+	*out = *in
+	if in.Neighbors != nil {
+		in, out := &in.Neighbors, &out.Neighbors
+		*out = make([]Neighbor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DynamicNeighbors != nil {
+		in, out := &in.DynamicNeighbors, &out.DynamicNeighbors
+		*out = make([]DynamicNeighbor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GracefulRestart != nil {
+		in, out := &in.GracefulRestart, &out.GracefulRestart
+		*out = new(GracefulRestart)
+		**out = **in
+	}
+	if in.Imports != nil {
+		in, out := &in.Imports, &out.Imports
+		*out = make([]Import, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VPN != nil {
+		in, out := &in.VPN, &out.VPN
+		*out = new(VPNLeak)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EVPN != nil {
+		in, out := &in.EVPN, &out.EVPN
+		*out = new(EVPN)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Redistribute.DeepCopyInto(&out.Redistribute)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Router.
+func (in *Router) DeepCopy() *Router {
+	if in == nil {
+		return nil
+	}
+	out := new(Router)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceAddressSelector) DeepCopyInto(out *SourceAddressSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceAddressSelector.
+func (in *SourceAddressSelector) DeepCopy() *SourceAddressSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceAddressSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticRoute) DeepCopyInto(out *StaticRoute) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticRoute.
+func (in *StaticRoute) DeepCopy() *StaticRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticRoutes) DeepCopyInto(out *StaticRoutes) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]StaticRoute, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticRoutes.
+func (in *StaticRoutes) DeepCopy() *StaticRoutes {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticRoutes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPNLeak) DeepCopyInto(out *VPNLeak) {
+	*out = *in
+	if in.ImportRouteTargets != nil {
+		in, out := &in.ImportRouteTargets, &out.ImportRouteTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExportRouteTargets != nil {
+		in, out := &in.ExportRouteTargets, &out.ExportRouteTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPNLeak.
+func (in *VPNLeak) DeepCopy() *VPNLeak {
+	if in == nil {
+		return nil
+	}
+	out := new(VPNLeak)
 	in.DeepCopyInto(out)
 	return out
 }
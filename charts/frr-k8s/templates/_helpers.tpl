@@ -61,3 +61,14 @@ Create the name of the frrk8s service account to use
 {{- default "default" .Values.frrk8s.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+The time zebra keeps the retained routes after a restart, in seconds
+*/}}
+{{- define "frrk8s.gracefulRestartTime" -}}
+{{- $time := int .Values.frrk8s.frr.gracefulRestart.restartTime }}
+{{- if or (lt $time 1) (gt $time 4095) }}
+{{- fail "frrk8s.frr.gracefulRestart.restartTime must be between 1 and 4095 seconds" }}
+{{- end }}
+{{- $time }}
+{{- end }}
@@ -63,6 +63,9 @@ func main() {
 		logLevel    string
 		nodeName    string
 		namespace   string
+		webhookMode string
+		webhookPort int
+		certDir     string
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "127.0.0.1:7572", "The address the metric endpoint binds to.")
@@ -70,6 +73,9 @@ func main() {
 	flag.StringVar(&logLevel, "log-level", "info", fmt.Sprintf("log level. must be one of: [%s]", logging.Levels.String()))
 	flag.StringVar(&nodeName, "node-name", "", "The node this daemon is running on.")
 	flag.StringVar(&namespace, "namespace", "", "The namespace this daemon is deployed in")
+	flag.StringVar(&webhookMode, "webhook-mode", "disabled", "webhook mode: can be enabled or disabled")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server listens on")
+	flag.StringVar(&certDir, "cert-dir", "/tmp/k8s-webhook-server/serving-certs", "The directory where the webhook certs are stored")
 
 	opts := zap.Options{
 		Development: true,
@@ -90,7 +96,8 @@ func main() {
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
-		Port:                   9443,
+		Port:                   webhookPort,
+		CertDir:                certDir,
 		HealthProbeBindAddress: probeAddr,
 		NewCache: cache.BuilderWithOptions(cache.Options{
 			SelectorsByObject: map[client.Object]cache.ObjectSelector{
@@ -104,16 +111,45 @@ func main() {
 	}
 
 	ctx := ctrl.SetupSignalHandler()
-	if err = (&controller.FRRConfigurationReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		FRRHandler: frr.NewFRR(ctx, logger, logging.Level(logLevel)),
-		Logger:     logger,
-		NodeName:   nodeName,
-	}).SetupWithManager(mgr); err != nil {
+	nodeStateUpdates, notifyNodeState := controller.NewNodeStateNotifier(nodeName)
+	frrInstance := frr.NewFRR(ctx, notifyNodeState, logger, logging.Level(logLevel))
+
+	configReconciler := &controller.FRRConfigurationReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		FRRHandler:    frrInstance,
+		Logger:        logger,
+		NodeName:      nodeName,
+		StatusChanged: notifyNodeState,
+		Recorder:      mgr.GetEventRecorderFor("frr-k8s"),
+	}
+	if err = configReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "FRRConfiguration")
 		os.Exit(1)
 	}
+	if err = (&controller.FRRNodeStateReconciler{
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		FRRStatus:        frrInstance,
+		ConversionStatus: configReconciler,
+		Logger:           logger,
+		NodeName:         nodeName,
+		Update:           nodeStateUpdates,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FRRNodeState")
+		os.Exit(1)
+	}
+	if webhookMode == "enabled" {
+		frrk8sv1beta1.Validator = &controller.ConfigValidator{
+			Client:    mgr.GetClient(),
+			Logger:    logger,
+			Namespace: namespace,
+		}
+		if err = (&frrk8sv1beta1.FRRConfiguration{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "FRRConfiguration")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
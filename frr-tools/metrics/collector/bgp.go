@@ -15,6 +15,8 @@ import (
 
 var labels = []string{"peer", "vrf"}
 
+var familyLabels = []string{"peer", "vrf", "family"}
+
 var (
 	sessionUpDesc = prometheus.NewDesc(
 		prometheus.BuildFQName(Namespace, Subsystem, SessionUp.Name),
@@ -37,6 +39,20 @@ var (
 		nil,
 	)
 
+	maxPrefixesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, Subsystem, MaxPrefixes.Name),
+		MaxPrefixes.Help,
+		familyLabels,
+		nil,
+	)
+
+	maxPrefixesReceivedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, Subsystem, MaxPrefixesReceived.Name),
+		MaxPrefixesReceived.Help,
+		familyLabels,
+		nil,
+	)
+
 	opensSentDesc = prometheus.NewDesc(
 		prometheus.BuildFQName(Namespace, Subsystem, "opens_sent"),
 		"Number of BGP open messages sent",
@@ -127,6 +143,8 @@ func (c *bgp) Describe(ch chan<- *prometheus.Desc) {
 	ch <- sessionUpDesc
 	ch <- prefixesDesc
 	ch <- receivedPrefixesDesc
+	ch <- maxPrefixesDesc
+	ch <- maxPrefixesReceivedDesc
 	ch <- opensSentDesc
 	ch <- opensReceivedDesc
 	ch <- notificationsSentDesc
@@ -171,6 +189,10 @@ func updateNeighborsMetrics(ch chan<- prometheus.Metric, neighbors map[string][]
 			ch <- prometheus.MustNewConstMetric(routeRefreshSentedDesc, prometheus.CounterValue, float64(n.MsgStats.RouteRefreshSent), peerLabel, vrf)
 			ch <- prometheus.MustNewConstMetric(totalSentDesc, prometheus.CounterValue, float64(n.MsgStats.TotalSent), peerLabel, vrf)
 			ch <- prometheus.MustNewConstMetric(totalReceivedDesc, prometheus.CounterValue, float64(n.MsgStats.TotalReceived), peerLabel, vrf)
+			for family, m := range n.MaxPrefixes {
+				ch <- prometheus.MustNewConstMetric(maxPrefixesDesc, prometheus.GaugeValue, float64(m.Limit), peerLabel, vrf, family)
+				ch <- prometheus.MustNewConstMetric(maxPrefixesReceivedDesc, prometheus.GaugeValue, float64(m.Received), peerLabel, vrf, family)
+			}
 		}
 	}
 }
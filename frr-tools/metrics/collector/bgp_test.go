@@ -4,6 +4,7 @@ package collector
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 	"text/template"
 
@@ -493,3 +494,51 @@ func TestCollect(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectMaxPrefixes(t *testing.T) {
+	neighbors := `{
+  "192.168.1.2":{
+    "remoteAs":65001,
+    "localAs":65000,
+    "bgpState":"Established",
+    "portForeign":179,
+    "addressFamilyInfo":{
+      "ipv4Unicast":{
+        "acceptedPrefixCounter":80,
+        "sentPrefixCounter":1,
+        "prefixAllowedMax":100,
+        "prefixAllowedWarningThresh":75
+      },
+      "ipv6Unicast":{
+        "acceptedPrefixCounter":3,
+        "sentPrefixCounter":1
+      }
+    }
+  }
+}`
+	expected := `
+	# HELP frrk8s_bgp_max_prefixes Maximum number of prefixes allowed to be received on the BGP session for the address family
+	# TYPE frrk8s_bgp_max_prefixes gauge
+	frrk8s_bgp_max_prefixes{family="ipv4Unicast", peer="192.168.1.2:179", vrf="default"} 100
+	# HELP frrk8s_bgp_max_prefixes_received Number of prefixes currently being received on the BGP session for an address family with a maximum set
+	# TYPE frrk8s_bgp_max_prefixes_received gauge
+	frrk8s_bgp_max_prefixes_received{family="ipv4Unicast", peer="192.168.1.2:179", vrf="default"} 80
+	`
+
+	collector := mocknewBGP(log.NewNopLogger())
+	cmdOutput := map[string]string{
+		"show bgp vrf all json":               vrfVtysh,
+		"show bgp vrf default neighbors json": neighbors,
+	}
+	collector.frrCli = func(args string) (string, error) {
+		res, ok := cmdOutput[args]
+		if !ok {
+			return "{}", nil
+		}
+		return res, nil
+	}
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "frrk8s_bgp_max_prefixes", "frrk8s_bgp_max_prefixes_received")
+	if err != nil {
+		t.Errorf("expected no error but got %s", err)
+	}
+}
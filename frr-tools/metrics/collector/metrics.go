@@ -30,4 +30,14 @@ var (
 		Name: "received_prefixes_total",
 		Help: "Number of prefixes currently being received on the BGP session",
 	}
+
+	MaxPrefixes = metric{
+		Name: "max_prefixes",
+		Help: "Maximum number of prefixes allowed to be received on the BGP session for the address family",
+	}
+
+	MaxPrefixesReceived = metric{
+		Name: "max_prefixes_received",
+		Help: "Number of prefixes currently being received on the BGP session for an address family with a maximum set",
+	}
 )
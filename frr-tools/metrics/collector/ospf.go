@@ -0,0 +1,64 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package collector
+
+import (
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/metallb/frrk8s/frr-tools/metrics/vtysh"
+	"github.com/metallb/frrk8s/internal/frr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const ospfSubsystem = "ospf"
+
+var (
+	ospfLabels = []string{"neighbor", "interface", "vrf"}
+
+	ospfNeighborFullDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, ospfSubsystem, "neighbor_full"),
+		"OSPF adjacency state (1 is full, 0 is any other state)",
+		ospfLabels,
+		nil,
+	)
+)
+
+type ospf struct {
+	Log    log.Logger
+	frrCli vtysh.Cli
+}
+
+func NewOSPF(l log.Logger) prometheus.Collector {
+	log := log.With(l, "collector", ospfSubsystem)
+	return &ospf{Log: log, frrCli: vtysh.Run}
+}
+
+func mockNewOSPF(l log.Logger) *ospf {
+	log := log.With(l, "collector", ospfSubsystem)
+	return &ospf{Log: log, frrCli: vtysh.Run}
+}
+
+func (c *ospf) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ospfNeighborFullDesc
+}
+
+func (c *ospf) Collect(ch chan<- prometheus.Metric) {
+	res, err := c.frrCli("show ip ospf vrf all neighbor json")
+	if err != nil {
+		level.Error(c.Log).Log("error", err, "msg", "failed to fetch OSPF neighbors from FRR")
+		return
+	}
+	neighbors, err := frr.ParseOSPFNeighbors(res)
+	if err != nil {
+		level.Error(c.Log).Log("error", err, "msg", "failed to parse OSPF neighbors")
+		return
+	}
+
+	for _, n := range neighbors {
+		full := 0
+		if n.Full() {
+			full = 1
+		}
+		ch <- prometheus.MustNewConstMetric(ospfNeighborFullDesc, prometheus.GaugeValue, float64(full), n.RouterID, n.Interface, n.VRF)
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package collector
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	ospfMetricsTmpl = `
+	# HELP frrk8s_ospf_neighbor_full OSPF adjacency state (1 is full, 0 is any other state)
+	# TYPE frrk8s_ospf_neighbor_full gauge
+	frrk8s_ospf_neighbor_full{interface="{{ .Interface }}", neighbor="{{ .Neighbor }}", vrf="{{ .VRF }}"} {{ .Full }}
+	`
+
+	ospfTests = []struct {
+		desc        string
+		vtyshOutput string
+		neighbor    string
+		iface       string
+		vrf         string
+		full        int
+	}{
+		{
+			desc:        "Full adjacency",
+			vtyshOutput: ospfNeighborsFull,
+			neighbor:    "10.0.0.2",
+			iface:       "eth0",
+			vrf:         "default",
+			full:        1,
+		},
+		{
+			desc:        "Adjacency being established",
+			vtyshOutput: ospfNeighborsExStart,
+			neighbor:    "10.0.0.3",
+			iface:       "eth1",
+			vrf:         "red",
+			full:        0,
+		},
+	}
+
+	ospfNeighborsFull = `
+	{
+		"default":{
+			"vrfName":"default",
+			"vrfId":0,
+			"neighbors":{
+				"10.0.0.2":[
+					{
+						"nbrPriority":1,
+						"nbrState":"Full/DR",
+						"converged":"Full",
+						"role":"DR",
+						"address":"192.168.1.2",
+						"ifaceName":"eth0:192.168.1.1"
+					}
+				]
+			}
+		}
+	}
+	`
+	ospfNeighborsExStart = `
+	{
+		"red":{
+			"vrfName":"red",
+			"vrfId":5,
+			"neighbors":{
+				"10.0.0.3":[
+					{
+						"nbrPriority":1,
+						"nbrState":"ExStart/DROther",
+						"converged":"ExStart",
+						"role":"DROther",
+						"address":"192.168.2.3",
+						"ifaceName":"eth1:192.168.2.1"
+					}
+				]
+			}
+		}
+	}
+	`
+)
+
+func TestOSPFCollect(t *testing.T) {
+	for _, test := range ospfTests {
+		t.Run(test.desc, func(t *testing.T) {
+			tmpl, err := template.New(test.desc).Parse(ospfMetricsTmpl)
+			if err != nil {
+				t.Errorf("expected no error but got %s", err)
+			}
+
+			var w bytes.Buffer
+			err = tmpl.Execute(&w, map[string]interface{}{
+				"Neighbor":  test.neighbor,
+				"Interface": test.iface,
+				"VRF":       test.vrf,
+				"Full":      test.full,
+			})
+			if err != nil {
+				t.Errorf("expected no error but got %s", err)
+			}
+
+			l := log.NewNopLogger()
+			collector := mockNewOSPF(l)
+			cmdOutput := map[string]string{
+				"show ip ospf vrf all neighbor json": test.vtyshOutput,
+			}
+			collector.frrCli = func(args string) (string, error) {
+				res, ok := cmdOutput[args]
+				if !ok {
+					return "{}", nil
+				}
+				return res, nil
+			}
+			buf := bytes.NewReader(w.Bytes())
+			err = testutil.CollectAndCompare(collector, buf)
+			if err != nil {
+				t.Errorf("expected no error but got %s", err)
+			}
+		})
+	}
+}
@@ -31,10 +31,12 @@ var (
 func metricsHandler(logger log.Logger) http.Handler {
 	BGPCollector := collector.NewBGP(logger)
 	BFDCollector := collector.NewBFD(logger)
+	OSPFCollector := collector.NewOSPF(logger)
 
 	registry := prometheus.NewRegistry()
 	registry.MustRegister(BGPCollector)
 	registry.MustRegister(BFDCollector)
+	registry.MustRegister(OSPFCollector)
 
 	gatherers := prometheus.Gatherers{
 		prometheus.DefaultGatherer,
@@ -5,6 +5,7 @@ package community
 import (
 	"errors"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 )
@@ -15,10 +16,30 @@ var (
 )
 
 // largeBGPCommunityMarker is the prefix that shall be used to indicate that a given community value is of type large
-// community. If and when extended BGP communities will be supported, the largeBGPCommunityMarker allows us to
-// distinguish between extended and large communities.
+// community. The largeBGPCommunityMarker allows us to distinguish between extended and large communities.
 const largeBGPCommunityMarker = "large"
 
+// The types of the supported extended communities.
+const (
+	RouteTarget   = "rt"
+	SiteOfOrigin  = "soo"
+	LinkBandwidth = "lb"
+)
+
+// wellKnownCommunities maps the names of the well-known communities
+// (RFC 1997, RFC 3765, RFC 7999, RFC 8326) to their values.
+var wellKnownCommunities = map[string]BGPCommunityLegacy{
+	"graceful-shutdown": {upperVal: 65535, lowerVal: 0},
+	"blackhole":         {upperVal: 65535, lowerVal: 666},
+	"no-export":         {upperVal: 65535, lowerVal: 65281},
+	"no-advertise":      {upperVal: 65535, lowerVal: 65282},
+	"local-as":          {upperVal: 65535, lowerVal: 65283},
+	"no-peer":           {upperVal: 65535, lowerVal: 65284},
+}
+
+// maxLinkBandwidth is the maximum link bandwidth, in Mbps, accepted by FRR.
+const maxLinkBandwidth = 25600
+
 // BGPCommunity represents a BGP community.
 type BGPCommunity interface {
 	LessThan(BGPCommunity) bool
@@ -29,11 +50,17 @@ type BGPCommunity interface {
 // Strings are parsed according to Juniper style  syntax (https://www.juniper.net/documentation/us/en/software/\
 // junos/routing-policy/bgp/topics/concept/policy-bgp-communities-extended-communities-match-conditions-overview.html
 // Legacy communities are of format "<AS number>:<community value>".
-// Extended communities (support of which is yet to be implemented) are of format "<type>:<administrator>:<assigned-number>".
+// Extended communities are of format "<type>:<administrator>:<assigned-number>", where type is one of
+// rt (route target), soo (site of origin) and lb (link bandwidth).
 // Large communities are of format large:<global administrator>:<localdata part 1>:<localdata part 2>.
+// The well-known communities can also be referenced by name (i.e. no-export, local-AS).
 func New(c string) (BGPCommunity, error) {
 	var bgpCommunity BGPCommunity
 
+	if wellKnown, ok := wellKnownCommunities[strings.ToLower(c)]; ok {
+		return wellKnown, nil
+	}
+
 	fs := strings.Split(c, ":")
 	switch l := len(fs); l {
 	case 2:
@@ -50,6 +77,8 @@ func New(c string) (BGPCommunity, error) {
 			upperVal: fields[0],
 			lowerVal: fields[1],
 		}, nil
+	case 3:
+		return newExtended(c, fs[0], fs[1], fs[2])
 	case 4:
 		if fs[0] != largeBGPCommunityMarker {
 			return bgpCommunity, fmt.Errorf("%w: invalid marker for large community, expected community to be of "+
@@ -117,6 +146,83 @@ func (b BGPCommunityLarge) String() string {
 	return fmt.Sprintf("%d:%d:%d", b.globalAdministrator, b.localDataPart1, b.localDataPart2)
 }
 
+// BGPCommunityExtended holds the internal representation of an extended BGP community.
+type BGPCommunityExtended struct {
+	extType        string
+	administrator  string
+	assignedNumber uint32
+}
+
+func newExtended(c, extType, administrator, assignedNumber string) (BGPCommunity, error) {
+	if extType != RouteTarget && extType != SiteOfOrigin && extType != LinkBandwidth {
+		return nil, fmt.Errorf("%w: invalid type %q for extended community %q, expected one of %s, %s, %s",
+			ErrInvalidCommunityValue, extType, c, RouteTarget, SiteOfOrigin, LinkBandwidth)
+	}
+
+	// The administrator is either an AS number or an IPv4 address, and the bits left
+	// of the 6 bytes of the value are used by the assigned number.
+	assignedNumberBits := 32
+	if ip := net.ParseIP(administrator); ip != nil && ip.To4() != nil && extType != LinkBandwidth {
+		assignedNumberBits = 16
+	} else {
+		asn, err := strconv.ParseUint(administrator, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid administrator %q of community %q, err: %q",
+				ErrInvalidCommunityValue, administrator, c, err)
+		}
+		if asn > 65535 {
+			assignedNumberBits = 16
+		}
+	}
+
+	n, err := strconv.ParseUint(assignedNumber, 10, assignedNumberBits)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid assigned number %q of community %q, err: %q",
+			ErrInvalidCommunityValue, assignedNumber, c, err)
+	}
+	if extType == LinkBandwidth && (n == 0 || n > maxLinkBandwidth) {
+		return nil, fmt.Errorf("%w: invalid bandwidth %d of community %q, must be between 1 and %d Mbps",
+			ErrInvalidCommunityValue, n, c, maxLinkBandwidth)
+	}
+
+	return BGPCommunityExtended{
+		extType:        extType,
+		administrator:  administrator,
+		assignedNumber: uint32(n),
+	}, nil
+}
+
+// LessThan makes 2 different BGPCommunity objects comparable. Extended communities are compared by
+// their string representation, and are considered greater than the legacy and large ones.
+func (b BGPCommunityExtended) LessThan(c BGPCommunity) bool {
+	e, ok := c.(BGPCommunityExtended)
+	if !ok {
+		return false
+	}
+	return b.String() < e.String()
+}
+
+// String returns the string representation of this community, as "<type>:<administrator>:<assigned-number>".
+func (b BGPCommunityExtended) String() string {
+	return fmt.Sprintf("%s:%s:%d", b.extType, b.administrator, b.assignedNumber)
+}
+
+// Type returns the type of this extended community.
+func (b BGPCommunityExtended) Type() string {
+	return b.extType
+}
+
+// ValidateForASN returns an error if this community can't be set by a router with the
+// given AS number. FRR always encodes the local AS number as the administrator of the
+// link bandwidth communities, so any other administrator would be silently replaced.
+func (b BGPCommunityExtended) ValidateForASN(asn uint32) error {
+	if b.extType != LinkBandwidth || b.administrator == strconv.FormatUint(uint64(asn), 10) {
+		return nil
+	}
+	return fmt.Errorf("%w: administrator %s of link bandwidth community %q must be the local AS number %d",
+		ErrInvalidCommunityValue, b.administrator, b.String(), asn)
+}
+
 // IsLegacy returns true if this is a Legacy community.
 func IsLegacy(c BGPCommunity) bool {
 	_, ok := c.(BGPCommunityLegacy)
@@ -129,8 +235,17 @@ func IsLarge(c BGPCommunity) bool {
 	return ok
 }
 
+// IsExtended returns true if this is an Extended community.
+func IsExtended(c BGPCommunity) bool {
+	_, ok := c.(BGPCommunityExtended)
+	return ok
+}
+
 // lessThan is a helper function that compares two communities regardless of their type.
 func lessThan(b BGPCommunity, c BGPCommunity) bool {
+	if IsExtended(c) {
+		return true
+	}
 	var bl BGPCommunityLarge
 	var cl BGPCommunityLarge
 	switch v := b.(type) {
@@ -37,9 +37,49 @@ func TestNewBGPCommunity(t *testing.T) {
 			input:       "large:12345:wrong:12345",
 			errorString: "invalid community value: invalid section",
 		},
-		"invalid extended community not yet implemented": {
+		"well-known community": {
+			input:  "no-export",
+			output: BGPCommunityLegacy{upperVal: 65535, lowerVal: 65281},
+		},
+		"well-known community, mixed case": {
+			input:  "local-AS",
+			output: BGPCommunityLegacy{upperVal: 65535, lowerVal: 65283},
+		},
+		"unknown community name": {
+			input:       "no-exports",
+			errorString: "invalid community format: no-exports",
+		},
+		"invalid extended community type": {
 			input:       "12345:12345:12345",
-			errorString: "invalid community format: 12345:12345:12345",
+			errorString: "invalid community value: invalid type",
+		},
+		"valid route target extended community": {
+			input:  "rt:65000:100",
+			output: BGPCommunityExtended{extType: RouteTarget, administrator: "65000", assignedNumber: 100},
+		},
+		"valid route target extended community with ip administrator": {
+			input:  "rt:192.0.2.1:100",
+			output: BGPCommunityExtended{extType: RouteTarget, administrator: "192.0.2.1", assignedNumber: 100},
+		},
+		"valid site of origin extended community with 4 bytes asn": {
+			input:  "soo:4200000000:100",
+			output: BGPCommunityExtended{extType: SiteOfOrigin, administrator: "4200000000", assignedNumber: 100},
+		},
+		"valid link bandwidth extended community": {
+			input:  "lb:65000:1000",
+			output: BGPCommunityExtended{extType: LinkBandwidth, administrator: "65000", assignedNumber: 1000},
+		},
+		"invalid extended community, assigned number too big for a 4 bytes asn": {
+			input:       "rt:4200000000:70000",
+			errorString: "invalid community value: invalid assigned number",
+		},
+		"invalid extended community, bad administrator": {
+			input:       "soo:foo:100",
+			errorString: "invalid community value: invalid administrator",
+		},
+		"invalid link bandwidth extended community": {
+			input:       "lb:65000:30000",
+			errorString: "invalid community value: invalid bandwidth",
 		},
 	}
 	for d, tc := range tcs {
@@ -70,10 +110,13 @@ func TestBGPCommunityLessThan(t *testing.T) {
 		right           string
 		expectedOutcome bool
 	}{
-		"compares legacy communities":           {left: "0:1234", right: "0:2345", expectedOutcome: true},
-		"compares large communities 1":          {left: "large:1234:0:0", right: "large:1234:0:1", expectedOutcome: true},
-		"compares large communities 2":          {left: "large:1235:0:0", right: "large:1234:1:0", expectedOutcome: false},
-		"compares legacy and large communities": {left: "0:1234", right: "large:123:456:789", expectedOutcome: false},
+		"compares legacy communities":              {left: "0:1234", right: "0:2345", expectedOutcome: true},
+		"compares large communities 1":             {left: "large:1234:0:0", right: "large:1234:0:1", expectedOutcome: true},
+		"compares large communities 2":             {left: "large:1235:0:0", right: "large:1234:1:0", expectedOutcome: false},
+		"compares legacy and large communities":    {left: "0:1234", right: "large:123:456:789", expectedOutcome: false},
+		"compares extended communities":            {left: "rt:65000:100", right: "soo:65000:100", expectedOutcome: true},
+		"compares large and extended communities":  {left: "large:123:456:789", right: "rt:65000:100", expectedOutcome: true},
+		"compares extended and legacy communities": {left: "rt:65000:100", right: "0:1234", expectedOutcome: false},
 	}
 	for d, tc := range tcs {
 		leftCommunity, _ := New(tc.left)
@@ -90,8 +133,9 @@ func TestBGPCommunityString(t *testing.T) {
 		input  string
 		output string
 	}{
-		"legacy community": {input: "0:1234", output: "0:1234"},
-		"large community":  {input: "large:1:2:3", output: "1:2:3"},
+		"legacy community":   {input: "0:1234", output: "0:1234"},
+		"large community":    {input: "large:1:2:3", output: "1:2:3"},
+		"extended community": {input: "rt:65000:100", output: "rt:65000:100"},
 	}
 	for d, tc := range tcs {
 		community, _ := New(tc.input)
@@ -100,3 +144,38 @@ func TestBGPCommunityString(t *testing.T) {
 		}
 	}
 }
+
+func TestBGPCommunityExtendedValidateForASN(t *testing.T) {
+	tcs := map[string]struct {
+		input string
+		asn   uint32
+		err   bool
+	}{
+		"link bandwidth with the local asn": {
+			input: "lb:65000:1000",
+			asn:   65000,
+		},
+		"link bandwidth with another asn": {
+			input: "lb:65001:1000",
+			asn:   65000,
+			err:   true,
+		},
+		"route target with another asn": {
+			input: "rt:65001:100",
+			asn:   65000,
+		},
+	}
+	for d, tc := range tcs {
+		c, err := New(tc.input)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %q", d, err)
+		}
+		err = c.(BGPCommunityExtended).ValidateForASN(tc.asn)
+		if tc.err && err == nil {
+			t.Fatalf("%s: expected an error but got <nil> instead", d)
+		}
+		if !tc.err && err != nil {
+			t.Fatalf("%s: unexpected error %q", d, err)
+		}
+	}
+}
@@ -5,13 +5,19 @@ package controller
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"net"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
 	"github.com/metallb/frrk8s/internal/community"
 	"github.com/metallb/frrk8s/internal/frr"
 	"github.com/metallb/frrk8s/internal/ipfamily"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -24,30 +30,66 @@ func (s SecretNotFoundError) Error() string {
 	return fmt.Sprintf("secret %s not found for neighbor %s", s.Name, s.Neighbor)
 }
 
+// clusterResources holds the resources, other than the FRRConfigurations, needed
+// to translate the configurations for a given node.
+type clusterResources struct {
+	passwordSecrets map[string]corev1.Secret
+	// node is the node the configuration is translated for. When nil, the
+	// values depending on the node are not resolved.
+	node *corev1.Node
+	// interfaceAddresses returns the addresses of the given local interface. When nil,
+	// the values depending on the local interfaces are not resolved.
+	interfaceAddresses func(name string) ([]net.IP, error)
+	// communityAliases maps the names of the community aliases to their values.
+	communityAliases map[string]string
+	// maintenance is the maintenance status of the node.
+	maintenance maintenanceStatus
+}
+
 type namedRawConfig struct {
 	v1beta1.RawConfig
 	configName string
 }
 
-func apiToFRR(fromK8s []v1beta1.FRRConfiguration, secrets map[string]corev1.Secret) (*frr.Config, error) {
+func apiToFRR(fromK8s []v1beta1.FRRConfiguration, resources clusterResources) (*frr.Config, error) {
 	res := &frr.Config{
 		Routers: make([]*frr.RouterConfig, 0),
-		//BFDProfiles: sm.bfdProfiles,
 	}
 
 	rawConfigs := make([]namedRawConfig, 0)
 	routersForVRF := map[string]*frr.RouterConfig{}
+	ospfRoutersForVRF := map[string]*frr.OSPFRouterConfig{}
+	staticRoutesForVRF := map[string]*frr.StaticRoutesConfig{}
+	bfdProfiles := map[string]*frr.BFDProfile{}
+	bfdProfileSources := map[string][]string{}
+	templates, err := templatesFor(fromK8s)
+	if err != nil {
+		return nil, err
+	}
 	for _, cfg := range fromK8s {
 		if cfg.Spec.Raw.Config != nil && len(cfg.Spec.Raw.Config) > 0 {
 			raw := namedRawConfig{RawConfig: cfg.Spec.Raw, configName: cfg.Name}
 			rawConfigs = append(rawConfigs, raw)
 		}
 
+		for _, p := range cfg.Spec.BGP.BFDProfiles {
+			var source []string
+			if s := sourceOf(cfg); s != "" {
+				source = []string{s}
+			}
+			err := mergeBFDProfiles(bfdProfiles, bfdProfileToFRR(p))
+			if err != nil {
+				return nil, withSources(err, bfdProfileSources[p.Name], source)
+			}
+			bfdProfileSources[p.Name] = mergeSources(bfdProfileSources[p.Name], source)
+		}
+
 		for _, r := range cfg.Spec.BGP.Routers {
-			routerCfg, err := routerToFRRConfig(r, secrets)
+			routerCfg, err := routerToFRRConfig(templates.resolve(r), resources)
 			if err != nil {
 				return nil, err
 			}
+			setSource(routerCfg, sourceOf(cfg))
 
 			curr, ok := routersForVRF[r.VRF]
 			if !ok {
@@ -62,15 +104,216 @@ func apiToFRR(fromK8s []v1beta1.FRRConfiguration, secrets map[string]corev1.Secr
 
 			routersForVRF[r.VRF] = curr
 		}
+
+		for _, r := range cfg.Spec.OSPF.Routers {
+			routerCfg, err := ospfRouterToFRR(r, resources.passwordSecrets)
+			if err != nil {
+				return nil, fmt.Errorf("failed to process ospf router for vrf %q: %w", r.VRF, err)
+			}
+			setOSPFSource(routerCfg, sourceOf(cfg))
+
+			curr, ok := ospfRoutersForVRF[r.VRF]
+			if !ok {
+				ospfRoutersForVRF[r.VRF] = routerCfg
+				continue
+			}
+
+			curr, err = mergeOSPFRouters(curr, routerCfg)
+			if err != nil {
+				return nil, err
+			}
+			ospfRoutersForVRF[r.VRF] = curr
+		}
+
+		for _, s := range cfg.Spec.StaticRoutes {
+			routesCfg, err := staticRoutesToFRR(s)
+			if err != nil {
+				return nil, fmt.Errorf("failed to process static routes for vrf %q: %w", s.VRF, err)
+			}
+			setStaticRoutesSource(routesCfg, sourceOf(cfg))
+
+			curr, ok := staticRoutesForVRF[s.VRF]
+			if !ok {
+				staticRoutesForVRF[s.VRF] = routesCfg
+				continue
+			}
+
+			curr.Routes, err = mergeStaticRoutes(s.VRF, curr.Routes, routesCfg.Routes)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	res.Routers = sortMapPtr(routersForVRF)
+	if len(ospfRoutersForVRF) > 0 {
+		res.OSPFRouters = sortMapPtr(ospfRoutersForVRF)
+	}
+	err = validateOSPFInterfaces(res.OSPFRouters)
+	if err != nil {
+		return nil, err
+	}
+	if len(staticRoutesForVRF) > 0 {
+		res.StaticRoutes = sortMapPtr(staticRoutesForVRF)
+	}
+	if len(bfdProfiles) > 0 {
+		res.BFDProfiles = sortMap(bfdProfiles)
+	}
+	err = validateBFDProfiles(res.Routers, bfdProfiles)
+	if err != nil {
+		return nil, err
+	}
+	err = validateStaticRoutesBFDProfiles(res.StaticRoutes, bfdProfiles)
+	if err != nil {
+		return nil, err
+	}
+	err = validateAdvertisedPrefixes(res.Routers)
+	if err != nil {
+		return nil, err
+	}
+	err = validateListenRanges(res.Routers)
+	if err != nil {
+		return nil, err
+	}
+	advertiseRedistributed(res.Routers)
+	for _, r := range res.Routers {
+		r.ListenLimit = listenLimitFor(r.Neighbors)
+	}
+	maintenance, err := maintenanceFor(fromK8s)
+	if err != nil {
+		return nil, err
+	}
+	applyMaintenance(res.Routers, maintenance, resources.maintenance)
 	res.ExtraConfig = joinRawConfigs(rawConfigs)
 
 	return res, nil
 }
 
-func routerToFRRConfig(r v1beta1.Router, secrets map[string]corev1.Secret) (*frr.RouterConfig, error) {
+// excludedConfig is a configuration left out of the translation, together with the reason.
+type excludedConfig struct {
+	config v1beta1.FRRConfiguration
+	err    error
+}
+
+// apiToFRRExcludingInvalid translates the given configurations, leaving out the ones that
+// can't be translated or that conflict with the others. When configurations conflict,
+// the oldest ones are preferred.
+// It returns the resulting config, the configurations used to produce it and the excluded ones.
+func apiToFRRExcludingInvalid(fromK8s []v1beta1.FRRConfiguration, resources clusterResources) (*frr.Config, []v1beta1.FRRConfiguration, []excludedConfig, error) {
+	cfgs := make([]v1beta1.FRRConfiguration, len(fromK8s))
+	copy(cfgs, fromK8s)
+	sort.SliceStable(cfgs, func(i, j int) bool {
+		if !cfgs[i].CreationTimestamp.Equal(&cfgs[j].CreationTimestamp) {
+			return cfgs[i].CreationTimestamp.Before(&cfgs[j].CreationTimestamp)
+		}
+		if cfgs[i].Namespace != cfgs[j].Namespace {
+			return cfgs[i].Namespace < cfgs[j].Namespace
+		}
+		return cfgs[i].Name < cfgs[j].Name
+	})
+
+	res, err := apiToFRR(cfgs, resources)
+	if err == nil {
+		return res, cfgs, nil, nil
+	}
+
+	excluded := []excludedConfig{}
+	valid := []v1beta1.FRRConfiguration{}
+	for _, cfg := range cfgs {
+		// A configuration may reference bfd profiles and templates defined in the others.
+		_, err := apiToFRR([]v1beta1.FRRConfiguration{*withExternalDefinitions(&cfg, cfgs)}, resources)
+		if err != nil {
+			excluded = append(excluded, excludedConfig{config: cfg, err: err})
+			continue
+		}
+		valid = append(valid, cfg)
+	}
+
+	res, err = apiToFRR(valid, resources)
+	if err == nil {
+		return res, valid, excluded, nil
+	}
+
+	accepted := []v1beta1.FRRConfiguration{}
+	for _, cfg := range valid {
+		_, err := apiToFRR(append(accepted, cfg), resources)
+		if err != nil {
+			excluded = append(excluded, excludedConfig{config: cfg, err: err})
+			continue
+		}
+		accepted = append(accepted, cfg)
+	}
+
+	res, err = apiToFRR(accepted, resources)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return res, accepted, excluded, nil
+}
+
+// sourceOf returns the namespace/name of the given configuration, used
+// to track where each part of the merged config comes from.
+func sourceOf(cfg v1beta1.FRRConfiguration) string {
+	if cfg.Name == "" {
+		return ""
+	}
+	return cfg.Namespace + "/" + cfg.Name
+}
+
+// setSource marks the router and all its parts as coming from the given source.
+func setSource(r *frr.RouterConfig, source string) {
+	if source == "" {
+		return
+	}
+	r.Sources = []string{source}
+	if len(r.IPV4Prefixes) > 0 || len(r.IPV6Prefixes) > 0 {
+		r.PrefixSources = map[string][]string{}
+	}
+	for _, p := range r.IPV4Prefixes {
+		r.PrefixSources[p] = []string{source}
+	}
+	for _, p := range r.IPV6Prefixes {
+		r.PrefixSources[p] = []string{source}
+	}
+	for _, n := range r.Neighbors {
+		n.Sources = []string{source}
+		for i := range n.Outgoing.PrefixesV4 {
+			n.Outgoing.PrefixesV4[i].Sources = []string{source}
+		}
+		for i := range n.Outgoing.PrefixesV6 {
+			n.Outgoing.PrefixesV6[i].Sources = []string{source}
+		}
+		for i := range n.Incoming.PrefixesV4 {
+			n.Incoming.PrefixesV4[i].Sources = []string{source}
+		}
+		for i := range n.Incoming.PrefixesV6 {
+			n.Incoming.PrefixesV6[i].Sources = []string{source}
+		}
+		for i := range n.Incoming.RangesV4 {
+			n.Incoming.RangesV4[i].Sources = []string{source}
+		}
+		for i := range n.Incoming.RangesV6 {
+			n.Incoming.RangesV6[i].Sources = []string{source}
+		}
+		for i := range n.Incoming.Policies {
+			n.Incoming.Policies[i].Sources = []string{source}
+		}
+	}
+	for i := range r.Imports {
+		r.Imports[i].Sources = []string{source}
+	}
+	for i := range r.RedistributeV4 {
+		r.RedistributeV4[i].Sources = []string{source}
+	}
+	for i := range r.RedistributeV6 {
+		r.RedistributeV6[i].Sources = []string{source}
+	}
+}
+
+func routerToFRRConfig(r v1beta1.Router, resources clusterResources) (*frr.RouterConfig, error) {
+	if r.ASNTemplate != "" {
+		return nil, fmt.Errorf("asnTemplate %s of the router at vrf %s is not resolved", r.ASNTemplate, r.VRF)
+	}
 	res := &frr.RouterConfig{
 		MyASN:        r.ASN,
 		RouterID:     r.ID,
@@ -92,10 +335,47 @@ func routerToFRRConfig(r v1beta1.Router, secrets map[string]corev1.Secret) (*frr
 		}
 	}
 
+	gracefulRestart, err := gracefulRestartToFRR(r.GracefulRestart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid graceful restart for router %d-%s: %w", r.ASN, r.VRF, err)
+	}
+	res.GracefulRestart = gracefulRestart
+
+	res.Imports, err = importsToFRR(r.VRF, r.Imports)
+	if err != nil {
+		return nil, fmt.Errorf("invalid imports for router %d-%s: %w", r.ASN, r.VRF, err)
+	}
+	res.VPN, err = vpnToFRR(r.VPN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vpn for router %d-%s: %w", r.ASN, r.VRF, err)
+	}
+	res.EVPN, err = evpnToFRR(r.VRF, r.EVPN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid evpn for router %d-%s: %w", r.ASN, r.VRF, err)
+	}
+	res.RedistributeV4, res.RedistributeV6, err = redistributeToFRR(r.VRF, r.Redistribute, resources.communityAliases)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redistribution for router %d-%s: %w", r.ASN, r.VRF, err)
+	}
+
 	for _, n := range r.Neighbors {
-		frrNeigh, err := neighborToFRR(n, res.IPV4Prefixes, res.IPV6Prefixes, secrets)
+		frrNeigh, err := neighborToFRR(n, res.IPV4Prefixes, res.IPV6Prefixes, resources)
+		if err == nil {
+			err = validateExtendedCommunities(frrNeigh.Outgoing, r.ASN)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to process neighbor %s for router %d-%s: %w", neighborName(n), r.ASN, r.VRF, err)
+		}
+		res.Neighbors = append(res.Neighbors, frrNeigh)
+	}
+
+	for _, d := range r.DynamicNeighbors {
+		frrNeigh, err := dynamicNeighborToFRR(d, res.IPV4Prefixes, res.IPV6Prefixes, resources)
+		if err == nil {
+			err = validateExtendedCommunities(frrNeigh.Outgoing, r.ASN)
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to process neighbor %s for router %d-%s: %w", neighborName(n.ASN, n.Address), r.ASN, r.VRF, err)
+			return nil, fmt.Errorf("failed to process dynamic neighbor %s for router %d-%s: %w", dynamicNeighborName(d), r.ASN, r.VRF, err)
 		}
 		res.Neighbors = append(res.Neighbors, frrNeigh)
 	}
@@ -103,40 +383,288 @@ func routerToFRRConfig(r v1beta1.Router, secrets map[string]corev1.Secret) (*frr
 	return res, nil
 }
 
-func neighborToFRR(n v1beta1.Neighbor, ipv4Prefixes, ipv6Prefixes []string, passwordSecrets map[string]corev1.Secret) (*frr.NeighborConfig, error) {
-	neighborFamily, err := ipfamily.ForAddresses(n.Address)
+func neighborToFRR(n v1beta1.Neighbor, ipv4Prefixes, ipv6Prefixes []string, resources clusterResources) (*frr.NeighborConfig, error) {
+	neighborFamily, err := familyForNeighbor(n)
+	if err != nil {
+		return nil, err
+	}
+	err = validateNeighborASN(n.ASN, n.DynamicASN)
+	if err != nil {
+		return nil, err
+	}
+	res := &frr.NeighborConfig{
+		Name:            neighborName(n),
+		ASN:             n.ASN,
+		DynamicASN:      string(n.DynamicASN),
+		Addr:            n.Address,
+		Iface:           n.Interface,
+		Port:            n.Port,
+		IPFamily:        neighborFamily,
+		EBGPMultiHop:    n.EBGPMultiHop,
+		ActivateEVPN:    n.ActivateEVPN,
+		BFDProfile:      n.BFDProfile,
+		GracefulRestart: string(n.GracefulRestart),
+	}
+
+	res.HoldTime, res.KeepaliveTime, err = timersForNeighbor(n.HoldTime, n.KeepaliveTime)
+	if err != nil {
+		return nil, err
+	}
+	res.MaxPrefixesV4, res.MaxPrefixesV6, err = maxPrefixesForNeighbor(n.MaxPrefixes)
+	if err != nil {
+		return nil, err
+	}
+	res.Password, err = passwordForNeighbor(n.PasswordSecret, res.Name, resources.passwordSecrets)
+	if err != nil {
+		return nil, err
+	}
+	res.SrcAddr, err = sourceAddressForNeighbor(n, neighborFamily, resources)
+	if err != nil {
+		return nil, err
+	}
+	res.Outgoing, err = toAdvertiseToFRR(n.ToAdvertise, ipv4Prefixes, ipv6Prefixes, resources.communityAliases)
+	if err != nil {
+		return nil, err
+	}
+	res.Incoming, err = toReceiveToFRR(n.ToReceive, resources.communityAliases)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// dynamicNeighborToFRR translates a dynamic neighbor to a neighbor accepting
+// the sessions from its listen range.
+func dynamicNeighborToFRR(d v1beta1.DynamicNeighbor, ipv4Prefixes, ipv6Prefixes []string, resources clusterResources) (*frr.NeighborConfig, error) {
+	_, cidr, err := net.ParseCIDR(d.ListenRange)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find ipfamily for %s, %w", n.Address, err)
+		return nil, fmt.Errorf("invalid listen range %s: %w", d.ListenRange, err)
+	}
+	if cidr.String() != d.ListenRange {
+		return nil, fmt.Errorf("invalid listen range %s: host bits must not be set, expected %s", d.ListenRange, cidr.String())
+	}
+	err = validateNeighborASN(d.ASN, d.DynamicASN)
+	if err != nil {
+		return nil, err
 	}
 	res := &frr.NeighborConfig{
-		Name:         neighborName(n.ASN, n.Address),
-		ASN:          n.ASN,
-		Addr:         n.Address,
-		Port:         n.Port,
-		IPFamily:     neighborFamily,
-		EBGPMultiHop: n.EBGPMultiHop,
+		Name:            dynamicNeighborName(d),
+		ASN:             d.ASN,
+		DynamicASN:      string(d.DynamicASN),
+		ListenRange:     d.ListenRange,
+		SessionLimit:    d.SessionLimit,
+		IPFamily:        ipfamily.ForCIDR(cidr),
+		EBGPMultiHop:    d.EBGPMultiHop,
+		ActivateEVPN:    d.ActivateEVPN,
+		BFDProfile:      d.BFDProfile,
+		GracefulRestart: string(d.GracefulRestart),
 	}
 
-	res.Password, err = passwordForNeighbor(n, passwordSecrets)
+	res.HoldTime, res.KeepaliveTime, err = timersForNeighbor(d.HoldTime, d.KeepaliveTime)
+	if err != nil {
+		return nil, err
+	}
+	res.MaxPrefixesV4, res.MaxPrefixesV6, err = maxPrefixesForNeighbor(d.MaxPrefixes)
 	if err != nil {
 		return nil, err
 	}
-	res.Outgoing, err = toAdvertiseToFRR(n.ToAdvertise, ipv4Prefixes, ipv6Prefixes)
+	res.Password, err = passwordForNeighbor(d.PasswordSecret, res.Name, resources.passwordSecrets)
+	if err != nil {
+		return nil, err
+	}
+	res.Outgoing, err = toAdvertiseToFRR(d.ToAdvertise, ipv4Prefixes, ipv6Prefixes, resources.communityAliases)
+	if err != nil {
+		return nil, err
+	}
+	res.Incoming, err = toReceiveToFRR(d.ToReceive, resources.communityAliases)
 	if err != nil {
 		return nil, err
 	}
-	res.Incoming = toReceiveToFRR(n.ToReceive)
 	return res, nil
 }
 
-func passwordForNeighbor(n v1beta1.Neighbor, passwordSecrets map[string]corev1.Secret) (string, error) {
-	if n.PasswordSecret.Name == "" {
+// familyForNeighbor returns the ip family of the session with the given neighbor.
+// Unnumbered sessions established over an interface carry both families.
+func familyForNeighbor(n v1beta1.Neighbor) (ipfamily.Family, error) {
+	if n.Address != "" && n.Interface != "" {
+		return ipfamily.Unknown, fmt.Errorf("address and interface are mutually exclusive")
+	}
+	if n.Interface != "" {
+		if n.SourceAddress != "" || n.SourceAddressFrom != nil {
+			return ipfamily.Unknown, fmt.Errorf("source address can't be set for the unnumbered session on interface %s", n.Interface)
+		}
+		return ipfamily.DualStack, nil
+	}
+	if net.ParseIP(n.Address) == nil {
+		return ipfamily.Unknown, fmt.Errorf("invalid address %s for neighbor", n.Address)
+	}
+	family, err := ipfamily.ForAddresses(n.Address)
+	if err != nil {
+		return ipfamily.Unknown, fmt.Errorf("failed to find ipfamily for %s, %w", n.Address, err)
+	}
+	return family, nil
+}
+
+func validateNeighborASN(asn uint32, dynamicASN v1beta1.DynamicASNMode) error {
+	if asn != 0 && dynamicASN != "" {
+		return fmt.Errorf("asn and dynamicASN are mutually exclusive")
+	}
+	if asn == 0 && dynamicASN == "" {
+		return fmt.Errorf("one of asn or dynamicASN must be specified")
+	}
+	if dynamicASN != "" && dynamicASN != v1beta1.InternalASNMode && dynamicASN != v1beta1.ExternalASNMode {
+		return fmt.Errorf("invalid dynamicASN %s, must be one of %s, %s", dynamicASN, v1beta1.InternalASNMode, v1beta1.ExternalASNMode)
+	}
+	return nil
+}
+
+// timersForNeighbor returns the hold and keepalive times (in seconds) to be used
+// for the given neighbor. When only one of the two is specified, the other is
+// derived using the 3:1 ratio suggested by RFC4271.
+func timersForNeighbor(hold, keepalive metav1.Duration) (uint64, uint64, error) {
+	holdTime := uint64(hold.Duration / time.Second)
+	keepaliveTime := uint64(keepalive.Duration / time.Second)
+
+	switch {
+	case holdTime == 0 && keepaliveTime == 0:
+		return 0, 0, nil
+	case holdTime == 0:
+		holdTime = keepaliveTime * 3
+	case keepaliveTime == 0:
+		keepaliveTime = holdTime / 3
+	}
+
+	if holdTime < 3 {
+		return 0, 0, fmt.Errorf("invalid hold time %ds: must be at least 3 seconds", holdTime)
+	}
+	if holdTime > 65535 {
+		return 0, 0, fmt.Errorf("invalid hold time %ds: must be at most 65535 seconds", holdTime)
+	}
+	if keepaliveTime > holdTime {
+		return 0, 0, fmt.Errorf("invalid keepalive time %ds: must be lower than the hold time %ds", keepaliveTime, holdTime)
+	}
+	return holdTime, keepaliveTime, nil
+}
+
+func gracefulRestartToFRR(gr *v1beta1.GracefulRestart) (*frr.GracefulRestart, error) {
+	if gr == nil {
+		return nil, nil
+	}
+	res := &frr.GracefulRestart{
+		Mode:          string(gr.Mode),
+		RestartTime:   uint64(gr.RestartTime.Duration / time.Second),
+		StalePathTime: uint64(gr.StalePathTime.Duration / time.Second),
+		LLGRStaleTime: uint64(gr.LongLivedStaleTime.Duration / time.Second),
+	}
+	if res.RestartTime > 4095 {
+		return nil, fmt.Errorf("invalid restart time %ds: must be at most 4095 seconds", res.RestartTime)
+	}
+	if res.StalePathTime > 4095 {
+		return nil, fmt.Errorf("invalid stale path time %ds: must be at most 4095 seconds", res.StalePathTime)
+	}
+	if res.LLGRStaleTime > 16777215 {
+		return nil, fmt.Errorf("invalid long lived stale time %ds: must be at most 16777215 seconds", res.LLGRStaleTime)
+	}
+	return res, nil
+}
+
+func evpnToFRR(vrf string, e *v1beta1.EVPN) (*frr.EVPN, error) {
+	if e == nil {
+		return nil, nil
+	}
+	if e.AdvertiseAllVNI && vrf != "" {
+		return nil, fmt.Errorf("advertiseAllVNI can be set only on the router of the default vrf")
+	}
+	if e.VNI != 0 && vrf == "" {
+		return nil, fmt.Errorf("vni can be set only on the routers of non default vrfs")
+	}
+	if e.VNI > 16777215 {
+		return nil, fmt.Errorf("invalid vni %d: must be at most 16777215", e.VNI)
+	}
+	if e.RouteDistinguisher != "" {
+		if err := validateRouteTarget(e.RouteDistinguisher); err != nil {
+			return nil, fmt.Errorf("invalid route distinguisher: %w", err)
+		}
+	}
+	for _, rt := range append(e.ImportRouteTargets, e.ExportRouteTargets...) {
+		if err := validateRouteTarget(rt); err != nil {
+			return nil, fmt.Errorf("invalid route target: %w", err)
+		}
+	}
+	res := &frr.EVPN{
+		AdvertiseAllVNI:    e.AdvertiseAllVNI,
+		VNI:                e.VNI,
+		RouteDistinguisher: e.RouteDistinguisher,
+		ImportRouteTargets: sets.List(sets.New(e.ImportRouteTargets...)),
+		ExportRouteTargets: sets.List(sets.New(e.ExportRouteTargets...)),
+	}
+	for _, f := range e.AdvertiseUnicast {
+		switch f {
+		case v1beta1.EVPNUnicastIPv4:
+			res.AdvertiseIPv4Unicast = true
+		case v1beta1.EVPNUnicastIPv6:
+			res.AdvertiseIPv6Unicast = true
+		default:
+			return nil, fmt.Errorf("unknown unicast family %s", f)
+		}
+	}
+	return res, nil
+}
+
+// maxPrefixesForNeighbor returns the limits of the prefixes received for each family.
+func maxPrefixesForNeighbor(m v1beta1.MaxPrefixes) (*frr.MaxPrefixes, *frr.MaxPrefixes, error) {
+	v4, err := prefixLimitToFRR(m.IPv4)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid ipv4 max prefixes: %w", err)
+	}
+	v6, err := prefixLimitToFRR(m.IPv6)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid ipv6 max prefixes: %w", err)
+	}
+	return v4, v6, nil
+}
+
+func prefixLimitToFRR(l *v1beta1.PrefixLimit) (*frr.MaxPrefixes, error) {
+	if l == nil {
+		return nil, nil
+	}
+	if l.Limit == 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+	if l.ThresholdPercentage > 100 {
+		return nil, fmt.Errorf("threshold percentage %d must be at most 100", l.ThresholdPercentage)
+	}
+	if l.WarningOnly && l.RestartInterval.Duration != 0 {
+		return nil, fmt.Errorf("warningOnly and restartInterval are mutually exclusive")
+	}
+	restart := l.RestartInterval.Duration
+	if restart%time.Minute != 0 {
+		return nil, fmt.Errorf("restart interval %s must be a whole number of minutes", restart)
+	}
+	if restart < 0 || restart > math.MaxUint16*time.Minute {
+		return nil, fmt.Errorf("restart interval %s must be between 1 and %d minutes", restart, math.MaxUint16)
+	}
+	return &frr.MaxPrefixes{
+		Limit:          l.Limit,
+		Threshold:      l.ThresholdPercentage,
+		WarningOnly:    l.WarningOnly,
+		RestartMinutes: uint32(restart / time.Minute),
+	}, nil
+}
+
+func passwordForNeighbor(ref corev1.SecretReference, neighbor string, passwordSecrets map[string]corev1.Secret) (string, error) {
+	if ref.Name == "" {
 		return "", nil
 	}
-	secret, ok := passwordSecrets[n.PasswordSecret.Name]
+	secret, ok := passwordSecrets[ref.Name]
 	if !ok {
-		return "", SecretNotFoundError{Name: n.PasswordSecret.Name, Neighbor: neighborName(n.ASN, n.Address)}
+		return "", SecretNotFoundError{Name: ref.Name, Neighbor: neighbor}
 	}
+	return passwordFromSecret(secret)
+}
+
+// passwordFromSecret returns the password held by the given basic-auth secret.
+func passwordFromSecret(secret corev1.Secret) (string, error) {
 	if secret.Type != corev1.SecretTypeBasicAuth {
 		return "", fmt.Errorf("secret type mismatch on %q/%q, type %q is expected ", secret.Namespace,
 			secret.Name, corev1.SecretTypeBasicAuth)
@@ -148,9 +676,12 @@ func passwordForNeighbor(n v1beta1.Neighbor, passwordSecrets map[string]corev1.S
 	return string(srcPass), nil
 }
 
-func toAdvertiseToFRR(toAdvertise v1beta1.Advertise, ipv4Prefixes, ipv6Prefixes []string) (frr.AllowedOut, error) {
-	advsV4, advsV6 := prefixesToMap(toAdvertise, ipv4Prefixes, ipv6Prefixes)
-	communities, err := communityPrefixesToMap(toAdvertise.PrefixesWithCommunity)
+func toAdvertiseToFRR(toAdvertise v1beta1.Advertise, ipv4Prefixes, ipv6Prefixes []string, aliases map[string]string) (frr.AllowedOut, error) {
+	advsV4, advsV6, err := prefixesToMap(toAdvertise, ipv4Prefixes, ipv6Prefixes)
+	if err != nil {
+		return frr.AllowedOut{}, err
+	}
+	communities, err := communityPrefixesToMap(toAdvertise.PrefixesWithCommunity, aliases)
 	if err != nil {
 		return frr.AllowedOut{}, err
 	}
@@ -174,15 +705,27 @@ func toAdvertiseToFRR(toAdvertise v1beta1.Advertise, ipv4Prefixes, ipv6Prefixes
 	if err != nil {
 		return frr.AllowedOut{}, err
 	}
+	err = setMEDToAdvertisements(advsV4, advsV6, toAdvertise.PrefixesWithMED)
+	if err != nil {
+		return frr.AllowedOut{}, err
+	}
+	err = setASPathPrependToAdvertisements(advsV4, advsV6, toAdvertise.PrefixesWithASPathPrepend)
+	if err != nil {
+		return frr.AllowedOut{}, err
+	}
 	res := frr.AllowedOut{
 		PrefixesV4: sortMap(advsV4),
 		PrefixesV6: sortMap(advsV6),
 	}
+	res.RedistributedV4, res.RedistributedV6, err = redistributedToAdvertise(toAdvertise.Allowed)
+	if err != nil {
+		return frr.AllowedOut{}, err
+	}
 	return res, nil
 }
 
 // prefixesToMap returns two maps of prefix->OutgoingFIlter (ie family, advertisement, communities), one for each family.
-func prefixesToMap(toAdvertise v1beta1.Advertise, ipv4Prefixes, ipv6Prefixes []string) (map[string]*frr.OutgoingFilter, map[string]*frr.OutgoingFilter) {
+func prefixesToMap(toAdvertise v1beta1.Advertise, ipv4Prefixes, ipv6Prefixes []string) (map[string]*frr.OutgoingFilter, map[string]*frr.OutgoingFilter, error) {
 	resV4 := map[string]*frr.OutgoingFilter{}
 	resV6 := map[string]*frr.OutgoingFilter{}
 	if toAdvertise.Allowed.Mode == v1beta1.AllowAll {
@@ -192,29 +735,63 @@ func prefixesToMap(toAdvertise v1beta1.Advertise, ipv4Prefixes, ipv6Prefixes []s
 		for _, p := range ipv6Prefixes {
 			resV6[p] = &frr.OutgoingFilter{Prefix: p, IPFamily: ipfamily.IPv6}
 		}
-		return resV4, resV6
+		return resV4, resV6, nil
+	}
+	ranges, err := prefixRangesFor(toAdvertise.Allowed.Ranges)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The ranges select the prefixes of the router, and take precedence over
+	// the listed prefixes.
+	denied := sets.New[string]()
+	for _, p := range ipv4Prefixes {
+		r, ok := firstMatchingRange(ranges, p)
+		if !ok {
+			continue
+		}
+		if r.deny {
+			denied.Insert(p)
+			continue
+		}
+		resV4[p] = &frr.OutgoingFilter{Prefix: p, IPFamily: ipfamily.IPv4}
+	}
+	for _, p := range ipv6Prefixes {
+		r, ok := firstMatchingRange(ranges, p)
+		if !ok {
+			continue
+		}
+		if r.deny {
+			denied.Insert(p)
+			continue
+		}
+		resV6[p] = &frr.OutgoingFilter{Prefix: p, IPFamily: ipfamily.IPv6}
 	}
-	// TODO: add a validation somewhere that checks that the prefixes are present in the
-	// global per router list.
 	for _, p := range toAdvertise.Allowed.Prefixes {
+		if denied.Has(p) {
+			continue
+		}
 		family := ipfamily.ForCIDRString(p)
 		switch family {
 		case ipfamily.IPv4:
 			resV4[p] = &frr.OutgoingFilter{Prefix: p, IPFamily: family}
 		case ipfamily.IPv6:
 			resV6[p] = &frr.OutgoingFilter{Prefix: p, IPFamily: family}
+		case ipfamily.Unknown:
+			return nil, nil, fmt.Errorf("unknown ipfamily for %s", p)
 		}
 	}
-	return resV4, resV6
+	return resV4, resV6, nil
 }
 
 // setCommunitiesToAdvertisements takes the given communityPrefixes and fills the relevant fields to the advertisements contained in the advs map.
 func setCommunitiesToAdvertisements(advs map[string]*frr.OutgoingFilter, communities communityPrefixes, ipFamily ipfamily.Family) error {
 	communitiesForPrefix := communities.communitiesForPrefixV4
 	largeCommunitiesForPrefix := communities.largeCommunitiesForPrefixV4
+	extendedCommunitiesForPrefix := communities.extendedCommunitiesForPrefixV4
 	if ipFamily == ipfamily.IPv6 {
 		communitiesForPrefix = communities.communitiesForPrefixV6
 		largeCommunitiesForPrefix = communities.largeCommunitiesForPrefixV6
+		extendedCommunitiesForPrefix = communities.extendedCommunitiesForPrefixV6
 	}
 	for p, c := range communitiesForPrefix {
 		adv, ok := advs[p]
@@ -231,6 +808,14 @@ func setCommunitiesToAdvertisements(advs map[string]*frr.OutgoingFilter, communi
 		}
 		adv.LargeCommunities = sets.List(c)
 	}
+
+	for p, c := range extendedCommunitiesForPrefix {
+		adv, ok := advs[p]
+		if !ok {
+			return fmt.Errorf("extended community associated to non existing prefix %s", p)
+		}
+		adv.ExtendedCommunities = sets.List(c)
+	}
 	return nil
 }
 
@@ -252,22 +837,162 @@ func setLocalPrefToAdvertisements(advs map[string]*frr.OutgoingFilter, localPref
 	return nil
 }
 
-func toReceiveToFRR(toReceive v1beta1.Receive) frr.AllowedIn {
+func receivePolicyToFRR(p v1beta1.ReceivePolicy, aliases map[string]string) (frr.IncomingPolicy, error) {
+	if p.LocalPref == nil && p.Weight == nil && len(p.SetCommunities) == 0 && !p.StripCommunities {
+		return frr.IncomingPolicy{}, fmt.Errorf("receive policy must specify at least one action")
+	}
+	if p.Weight != nil && *p.Weight > math.MaxUint16 {
+		return frr.IncomingPolicy{}, fmt.Errorf("invalid weight %d: must be lower than %d", *p.Weight, math.MaxUint16+1)
+	}
+	res := frr.IncomingPolicy{
+		LocalPref:        p.LocalPref,
+		Weight:           p.Weight,
+		StripCommunities: p.StripCommunities,
+	}
+	for _, pfx := range p.Prefixes {
+		switch ipfamily.ForCIDRString(pfx) {
+		case ipfamily.IPv4:
+			res.PrefixesV4 = append(res.PrefixesV4, pfx)
+		case ipfamily.IPv6:
+			res.PrefixesV6 = append(res.PrefixesV6, pfx)
+		case ipfamily.Unknown:
+			return frr.IncomingPolicy{}, fmt.Errorf("unknown ipfamily for %s", pfx)
+		}
+	}
+	var err error
+	res.Communities, res.LargeCommunities, err = policyCommunities(p.Communities, aliases)
+	if err != nil {
+		return frr.IncomingPolicy{}, err
+	}
+	res.SetCommunities, res.SetLargeCommunities, err = policyCommunities(p.SetCommunities, aliases)
+	if err != nil {
+		return frr.IncomingPolicy{}, err
+	}
+	return res, nil
+}
+
+// policyCommunities splits the given communities of a receive policy in
+// standard and large ones.
+func policyCommunities(communities []string, aliases map[string]string) ([]string, []string, error) {
+	var standard, large []string
+	for _, c := range communities {
+		comm, err := communityFor(c, aliases)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case community.IsLarge(comm):
+			large = append(large, comm.String())
+		case community.IsExtended(comm):
+			return nil, nil, fmt.Errorf("extended community %s can't be used in receive policies", c)
+		default:
+			standard = append(standard, comm.String())
+		}
+	}
+	return standard, large, nil
+}
+
+// setMEDToAdvertisements sets the MED of the given advertisements, split by family.
+func setMEDToAdvertisements(advsV4, advsV6 map[string]*frr.OutgoingFilter, withMED []v1beta1.MEDPrefixes) error {
+	for _, pfxs := range withMED {
+		for _, p := range pfxs.Prefixes {
+			adv, err := advertisementFor(p, advsV4, advsV6)
+			if err != nil {
+				return fmt.Errorf("med associated to %w", err)
+			}
+			if adv.MED != nil {
+				return fmt.Errorf("multiple meds specified for prefix %s", p)
+			}
+			med := pfxs.MED
+			adv.MED = &med
+		}
+	}
+	return nil
+}
+
+// setASPathPrependToAdvertisements sets the AS path prepend of the given advertisements, split by family.
+func setASPathPrependToAdvertisements(advsV4, advsV6 map[string]*frr.OutgoingFilter, withPrepend []v1beta1.ASPathPrependPrefixes) error {
+	for _, pfxs := range withPrepend {
+		if pfxs.ASN != 0 && pfxs.LastAS {
+			return fmt.Errorf("asn and lastAS are mutually exclusive in as-path prepend")
+		}
+		if pfxs.ASN == 0 && !pfxs.LastAS {
+			return fmt.Errorf("one of asn or lastAS must be specified in as-path prepend")
+		}
+		if pfxs.Count < 1 || pfxs.Count > 10 {
+			return fmt.Errorf("invalid as-path prepend count %d: must be between 1 and 10", pfxs.Count)
+		}
+		for _, p := range pfxs.Prefixes {
+			adv, err := advertisementFor(p, advsV4, advsV6)
+			if err != nil {
+				return fmt.Errorf("as-path prepend associated to %w", err)
+			}
+			if adv.ASPathPrepend != nil {
+				return fmt.Errorf("multiple as-path prepends specified for prefix %s", p)
+			}
+			adv.ASPathPrepend = &frr.ASPathPrepend{
+				ASN:    pfxs.ASN,
+				LastAS: pfxs.LastAS,
+				Count:  pfxs.Count,
+			}
+		}
+	}
+	return nil
+}
+
+// advertisementFor returns the advertisement of the given prefix, looking into the map of its family.
+func advertisementFor(prefix string, advsV4, advsV6 map[string]*frr.OutgoingFilter) (*frr.OutgoingFilter, error) {
+	advs := advsV4
+	switch ipfamily.ForCIDRString(prefix) {
+	case ipfamily.IPv6:
+		advs = advsV6
+	case ipfamily.Unknown:
+		return nil, fmt.Errorf("unknown ipfamily for %s", prefix)
+	}
+	adv, ok := advs[prefix]
+	if !ok {
+		return nil, fmt.Errorf("non existing prefix %s", prefix)
+	}
+	return adv, nil
+}
+
+func toReceiveToFRR(toReceive v1beta1.Receive, aliases map[string]string) (frr.AllowedIn, error) {
 	res := frr.AllowedIn{
 		PrefixesV4: make([]frr.IncomingFilter, 0),
 		PrefixesV6: make([]frr.IncomingFilter, 0),
 	}
+	for _, p := range toReceive.Policies {
+		policy, err := receivePolicyToFRR(p, aliases)
+		if err != nil {
+			return frr.AllowedIn{}, err
+		}
+		res.Policies = append(res.Policies, policy)
+	}
 	if toReceive.Allowed.Mode == v1beta1.AllowAll {
 		res.All = true
-		return res
+		return res, nil
+	}
+	ranges, err := prefixRangesFor(toReceive.Allowed.Ranges)
+	if err != nil {
+		return frr.AllowedIn{}, err
+	}
+	for _, r := range ranges {
+		if r.ipFamily == ipfamily.IPv6 {
+			res.RangesV6 = append(res.RangesV6, r.toFRR())
+			continue
+		}
+		res.RangesV4 = append(res.RangesV4, r.toFRR())
 	}
 	for _, p := range toReceive.Allowed.Prefixes {
 		family := ipfamily.ForCIDRString(p)
-		if family == ipfamily.IPv4 {
+		switch family {
+		case ipfamily.IPv4:
 			res.PrefixesV4 = append(res.PrefixesV4, frr.IncomingFilter{Prefix: p, IPFamily: family})
-			continue
+		case ipfamily.IPv6:
+			res.PrefixesV6 = append(res.PrefixesV6, frr.IncomingFilter{Prefix: p, IPFamily: family})
+		case ipfamily.Unknown:
+			return frr.AllowedIn{}, fmt.Errorf("unknown ipfamily for %s", p)
 		}
-		res.PrefixesV6 = append(res.PrefixesV6, frr.IncomingFilter{Prefix: p, IPFamily: family})
 	}
 	sort.Slice(res.PrefixesV4, func(i, j int) bool {
 		return res.PrefixesV4[i].Prefix < res.PrefixesV4[j].Prefix
@@ -275,53 +1000,196 @@ func toReceiveToFRR(toReceive v1beta1.Receive) frr.AllowedIn {
 	sort.Slice(res.PrefixesV6, func(i, j int) bool {
 		return res.PrefixesV6[i].Prefix < res.PrefixesV6[j].Prefix
 	})
+	return res, nil
+}
+
+func bfdProfileToFRR(p v1beta1.BFDProfile) *frr.BFDProfile {
+	res := &frr.BFDProfile{
+		Name:             p.Name,
+		ReceiveInterval:  uint32PtrOrNil(p.ReceiveInterval),
+		TransmitInterval: uint32PtrOrNil(p.TransmitInterval),
+		DetectMultiplier: uint32PtrOrNil(p.DetectMultiplier),
+		EchoInterval:     uint32PtrOrNil(p.EchoInterval),
+		EchoMode:         p.EchoMode,
+		PassiveMode:      p.PassiveMode,
+		MinimumTTL:       uint32PtrOrNil(p.MinimumTTL),
+	}
 	return res
 }
 
-func neighborName(ASN uint32, peerAddr string) string {
-	return fmt.Sprintf("%d@%s", ASN, peerAddr)
+func uint32PtrOrNil(v uint32) *uint32 {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+// validateBFDProfiles verifies that all the bfd profiles referenced by the neighbors
+// are defined in the given profiles.
+func validateBFDProfiles(routers []*frr.RouterConfig, profiles map[string]*frr.BFDProfile) error {
+	for _, r := range routers {
+		for _, n := range r.Neighbors {
+			if n.BFDProfile == "" {
+				continue
+			}
+			if _, ok := profiles[n.BFDProfile]; !ok {
+				return fmt.Errorf("neighbor %s at vrf %s references non existing bfd profile %s", n.Peer(), r.VRF, n.BFDProfile)
+			}
+		}
+	}
+	return nil
+}
+
+// validateAdvertisedPrefixes verifies that the prefixes advertised to the neighbors
+// are among the prefixes of the (merged) router they belong to, or among the
+// routes it redistributes.
+func validateAdvertisedPrefixes(routers []*frr.RouterConfig) error {
+	for _, r := range routers {
+		routerPrefixes := sets.New(r.IPV4Prefixes...).Insert(r.IPV6Prefixes...)
+		for _, n := range r.Neighbors {
+			for _, p := range n.Outgoing.AllPrefixes() {
+				if routerPrefixes.Has(p.Prefix) {
+					continue
+				}
+				redistributions := r.RedistributeV4
+				if p.IPFamily == ipfamily.IPv6 {
+					redistributions = r.RedistributeV6
+				}
+				if !redistributionsMatch(redistributions, p.Prefix) {
+					return fmt.Errorf("prefix %s advertised to neighbor %s is not among the prefixes of router %d-%s", p.Prefix, n.Peer(), r.MyASN, r.VRF)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateListenRanges verifies that the listen ranges of the dynamic neighbors
+// of a router do not overlap, as a session would match multiple peer-groups.
+func validateListenRanges(routers []*frr.RouterConfig) error {
+	for _, r := range routers {
+		dynamic := []*frr.NeighborConfig{}
+		for _, n := range r.Neighbors {
+			if n.ListenRange == "" {
+				continue
+			}
+			_, cidr, err := net.ParseCIDR(n.ListenRange)
+			if err != nil {
+				return fmt.Errorf("invalid listen range %s: %w", n.ListenRange, err)
+			}
+			for _, other := range dynamic {
+				_, otherCidr, _ := net.ParseCIDR(other.ListenRange)
+				if otherCidr.Contains(cidr.IP) || cidr.Contains(otherCidr.IP) {
+					err := fmt.Errorf("listen range %s overlaps with %s in router %d-%s", n.ListenRange, other.ListenRange, r.MyASN, r.VRF)
+					return withSources(err, other.Sources, n.Sources)
+				}
+			}
+			dynamic = append(dynamic, n)
+		}
+	}
+	return nil
+}
+
+// defaultSessionLimit is the number of dynamic sessions FRR accepts by default.
+const defaultSessionLimit = 100
+
+// listenLimitFor returns the session limit of a router, which is the sum of the limits
+// of its dynamic neighbors. Zero is returned, meaning FRR's default, when no
+// dynamic neighbor has a limit.
+func listenLimitFor(neighbors []*frr.NeighborConfig) uint32 {
+	var res uint32
+	withLimit := false
+	for _, n := range neighbors {
+		if n.ListenRange == "" {
+			continue
+		}
+		if n.SessionLimit == 0 {
+			res += defaultSessionLimit
+			continue
+		}
+		res += n.SessionLimit
+		withLimit = true
+	}
+	if !withLimit {
+		return 0
+	}
+	if res > math.MaxUint16 {
+		return math.MaxUint16
+	}
+	return res
+}
+
+func neighborName(n v1beta1.Neighbor) string {
+	asn := strconv.FormatUint(uint64(n.ASN), 10)
+	if n.DynamicASN != "" {
+		asn = string(n.DynamicASN)
+	}
+	peer := n.Address
+	if n.Interface != "" {
+		peer = n.Interface
+	}
+	return fmt.Sprintf("%s@%s", asn, peer)
+}
+
+func dynamicNeighborName(d v1beta1.DynamicNeighbor) string {
+	if d.DynamicASN != "" {
+		return fmt.Sprintf("%s@%s", d.DynamicASN, d.ListenRange)
+	}
+	return fmt.Sprintf("%d@%s", d.ASN, d.ListenRange)
 }
 
 type communityPrefixes struct {
-	communitiesForPrefixV4      map[string]sets.Set[string]
-	largeCommunitiesForPrefixV4 map[string]sets.Set[string]
-	communitiesForPrefixV6      map[string]sets.Set[string]
-	largeCommunitiesForPrefixV6 map[string]sets.Set[string]
+	communitiesForPrefixV4         map[string]sets.Set[string]
+	largeCommunitiesForPrefixV4    map[string]sets.Set[string]
+	extendedCommunitiesForPrefixV4 map[string]sets.Set[string]
+	communitiesForPrefixV6         map[string]sets.Set[string]
+	largeCommunitiesForPrefixV6    map[string]sets.Set[string]
+	extendedCommunitiesForPrefixV6 map[string]sets.Set[string]
 }
 
-func (c *communityPrefixes) mapFor(family ipfamily.Family, isLarge bool) map[string]sets.Set[string] {
+func (c *communityPrefixes) mapFor(family ipfamily.Family, comm community.BGPCommunity) map[string]sets.Set[string] {
 	switch family {
 	case ipfamily.IPv4:
-		if isLarge {
+		if community.IsLarge(comm) {
 			return c.largeCommunitiesForPrefixV4
 		}
+		if community.IsExtended(comm) {
+			return c.extendedCommunitiesForPrefixV4
+		}
 		return c.communitiesForPrefixV4
 	case ipfamily.IPv6:
-		if isLarge {
+		if community.IsLarge(comm) {
 			return c.largeCommunitiesForPrefixV6
 		}
+		if community.IsExtended(comm) {
+			return c.extendedCommunitiesForPrefixV6
+		}
 		return c.communitiesForPrefixV6
 	}
 	return nil
 }
 
-func communityPrefixesToMap(withCommunity []v1beta1.CommunityPrefixes) (communityPrefixes, error) {
+func communityPrefixesToMap(withCommunity []v1beta1.CommunityPrefixes, aliases map[string]string) (communityPrefixes, error) {
 	res := communityPrefixes{
-		communitiesForPrefixV4:      map[string]sets.Set[string]{},
-		largeCommunitiesForPrefixV4: map[string]sets.Set[string]{},
-		communitiesForPrefixV6:      map[string]sets.Set[string]{},
-		largeCommunitiesForPrefixV6: map[string]sets.Set[string]{},
+		communitiesForPrefixV4:         map[string]sets.Set[string]{},
+		largeCommunitiesForPrefixV4:    map[string]sets.Set[string]{},
+		extendedCommunitiesForPrefixV4: map[string]sets.Set[string]{},
+		communitiesForPrefixV6:         map[string]sets.Set[string]{},
+		largeCommunitiesForPrefixV6:    map[string]sets.Set[string]{},
+		extendedCommunitiesForPrefixV6: map[string]sets.Set[string]{},
 	}
 
 	for _, pfxs := range withCommunity {
-		c, err := community.New(pfxs.Community)
+		c, err := communityFor(pfxs.Community, aliases)
 		if err != nil {
-			return communityPrefixes{}, fmt.Errorf("invalid community %s, err: %w", pfxs.Community, err)
+			return communityPrefixes{}, err
 		}
-		isLarge := community.IsLarge(c)
 		for _, p := range pfxs.Prefixes {
 			family := ipfamily.ForCIDRString(p)
-			communityMap := res.mapFor(family, isLarge)
+			if family == ipfamily.Unknown {
+				return communityPrefixes{}, fmt.Errorf("unknown ipfamily for %s", p)
+			}
+			communityMap := res.mapFor(family, c)
 			_, ok := communityMap[p]
 			if !ok {
 				communityMap[p] = sets.New(c.String())
@@ -334,6 +1202,48 @@ func communityPrefixesToMap(withCommunity []v1beta1.CommunityPrefixes) (communit
 	return res, nil
 }
 
+// validateExtendedCommunities verifies that the extended communities advertised
+// to a neighbor can be set by the router with the given AS number.
+func validateExtendedCommunities(out frr.AllowedOut, asn uint32) error {
+	for _, adv := range append(append([]frr.OutgoingFilter{}, out.PrefixesV4...), out.PrefixesV6...) {
+		for _, c := range adv.ExtendedCommunities {
+			comm, err := community.New(c)
+			if err != nil {
+				return err
+			}
+			extended, ok := comm.(community.BGPCommunityExtended)
+			if !ok {
+				continue
+			}
+			if err := extended.ValidateForASN(asn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// communityFor parses the given community. Values that are not communities
+// are resolved using the given aliases.
+func communityFor(c string, aliases map[string]string) (community.BGPCommunity, error) {
+	res, err := community.New(c)
+	if err == nil {
+		return res, nil
+	}
+	if strings.Contains(c, ":") {
+		return nil, fmt.Errorf("invalid community %s, err: %w", c, err)
+	}
+	value, ok := aliases[c]
+	if !ok {
+		return nil, fmt.Errorf("unknown community alias %s", c)
+	}
+	res, err = community.New(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %s for community alias %s, err: %w", value, c, err)
+	}
+	return res, nil
+}
+
 type localPrefPrefixes struct {
 	localPrefForPrefixV4 map[string]uint32
 	localPrefForPrefixV6 map[string]uint32
@@ -348,6 +1258,9 @@ func localPrefPrefixesToMap(withLocalPref []v1beta1.LocalPrefPrefixes) (localPre
 	for _, pfxs := range withLocalPref {
 		for _, p := range pfxs.Prefixes {
 			family := ipfamily.ForCIDRString(p)
+			if family == ipfamily.Unknown {
+				return localPrefPrefixes{}, fmt.Errorf("unknown ipfamily for %s", p)
+			}
 			lpMap := res.localPrefForPrefixV4
 			if family == ipfamily.IPv6 {
 				lpMap = res.localPrefForPrefixV6
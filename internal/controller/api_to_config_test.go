@@ -5,13 +5,17 @@ package controller
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
 	"github.com/metallb/frrk8s/internal/frr"
 	"github.com/metallb/frrk8s/internal/ipfamily"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 func TestConversion(t *testing.T) {
@@ -570,7 +574,7 @@ func TestConversion(t *testing.T) {
 											},
 										},
 									},
-									Prefixes: []string{"192.0.2.0/24", "192.0.3.0/24", "192.0.4.0/24", "2001:db8::/64"},
+									Prefixes: []string{"192.0.2.0/24", "192.0.3.0/24", "192.0.4.0/24", "192.0.6.0/24", "2001:db8::/64"},
 								},
 							},
 						},
@@ -641,6 +645,10 @@ func TestConversion(t *testing.T) {
 											Prefix:      "192.0.4.0/24",
 											Communities: []string{"10:100"},
 										},
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "192.0.6.0/24",
+										},
 									},
 									PrefixesV6: []frr.OutgoingFilter{
 										{
@@ -656,7 +664,7 @@ func TestConversion(t *testing.T) {
 								},
 							},
 						},
-						IPV4Prefixes: []string{"192.0.2.0/24", "192.0.3.0/24", "192.0.4.0/24"},
+						IPV4Prefixes: []string{"192.0.2.0/24", "192.0.3.0/24", "192.0.4.0/24", "192.0.6.0/24"},
 						IPV6Prefixes: []string{"2001:db8::/64"},
 					},
 				},
@@ -1590,20 +1598,3003 @@ func TestConversion(t *testing.T) {
 			},
 			err: nil,
 		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			frr, err := apiToFRR(test.fromK8s, test.secrets)
-			if test.err != nil && err == nil {
-				t.Fatalf("expected error, got nil")
-			}
-			if test.err == nil && err != nil {
-				t.Fatalf("expected no error, got %v", err)
-			}
-			if diff := cmp.Diff(frr, test.expected); diff != "" {
-				t.Fatalf("config different from expected: %s", diff)
-			}
-		})
-	}
+		{
+			name: "Neighbor with timers and BFD profiles from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									ID:  "192.0.2.1",
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:           65002,
+											Address:       "192.0.2.2",
+											HoldTime:      metav1.Duration{Duration: 90 * time.Second},
+											KeepaliveTime: metav1.Duration{Duration: 30 * time.Second},
+											BFDProfile:    "bfd1",
+										},
+									},
+								},
+							},
+							BFDProfiles: []v1beta1.BFDProfile{
+								{
+									Name:            "bfd1",
+									ReceiveInterval: 100,
+									EchoMode:        true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:      65003,
+											Address:  "192.0.2.3",
+											HoldTime: metav1.Duration{Duration: 30 * time.Second},
+										},
+									},
+								},
+							},
+							BFDProfiles: []v1beta1.BFDProfile{
+								{
+									Name:            "bfd1",
+									ReceiveInterval: 100,
+									EchoMode:        true,
+								},
+								{
+									Name:             "bfd2",
+									DetectMultiplier: 5,
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN:    65001,
+						RouterID: "192.0.2.1",
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily:      ipfamily.IPv4,
+								Name:          "65002@192.0.2.2",
+								ASN:           65002,
+								Addr:          "192.0.2.2",
+								HoldTime:      90,
+								KeepaliveTime: 30,
+								BFDProfile:    "bfd1",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+							{
+								IPFamily:      ipfamily.IPv4,
+								Name:          "65003@192.0.2.3",
+								ASN:           65003,
+								Addr:          "192.0.2.3",
+								HoldTime:      30,
+								KeepaliveTime: 10,
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						IPV4Prefixes: []string{},
+						IPV6Prefixes: []string{},
+					},
+				},
+				BFDProfiles: []frr.BFDProfile{
+					{
+						Name:            "bfd1",
+						ReceiveInterval: pointer.Uint32(100),
+						EchoMode:        true,
+					},
+					{
+						Name:             "bfd2",
+						DetectMultiplier: pointer.Uint32(5),
+					},
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "Neighbor with non existing BFD profile",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:        65002,
+											Address:    "192.0.2.2",
+											BFDProfile: "bfd1",
+										},
+									},
+								},
+							},
+							BFDProfiles: []v1beta1.BFDProfile{
+								{
+									Name: "bfd2",
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("neighbor 192.0.2.2 at vrf  references non existing bfd profile bfd1"),
+		},
+		{
+			name: "Conflicting BFD profiles from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							BFDProfiles: []v1beta1.BFDProfile{
+								{
+									Name:            "bfd1",
+									ReceiveInterval: 100,
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							BFDProfiles: []v1beta1.BFDProfile{
+								{
+									Name:            "bfd1",
+									ReceiveInterval: 200,
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("multiple bfd profiles specified for bfd1 with different values"),
+		},
+		{
+			name: "Neighbor with keepalive time greater than hold time",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:           65002,
+											Address:       "192.0.2.2",
+											HoldTime:      metav1.Duration{Duration: 30 * time.Second},
+											KeepaliveTime: metav1.Duration{Duration: 60 * time.Second},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("failed to process neighbor 65002@192.0.2.2 for router 65001-: invalid keepalive time 60s: must be lower than the hold time 30s"),
+		},
+		{
+			name: "Unnumbered neighbor merged with a numbered one",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											DynamicASN: v1beta1.ExternalASNMode,
+											Interface:  "swp1",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65002,
+											Address: "192.0.2.2",
+										},
+										{
+											DynamicASN: v1beta1.ExternalASNMode,
+											Interface:  "swp1",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Mode: v1beta1.AllowAll,
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.2.0/24", "2001:db8::/64"},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65001,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily: ipfamily.IPv4,
+								Name:     "65002@192.0.2.2",
+								ASN:      65002,
+								Addr:     "192.0.2.2",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+							{
+								IPFamily:   ipfamily.DualStack,
+								Name:       "external@swp1",
+								DynamicASN: "external",
+								Iface:      "swp1",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "192.0.2.0/24",
+										},
+									},
+									PrefixesV6: []frr.OutgoingFilter{
+										{
+											IPFamily: ipfamily.IPv6,
+											Prefix:   "2001:db8::/64",
+										},
+									},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						IPV4Prefixes: []string{"192.0.2.0/24"},
+						IPV6Prefixes: []string{"2001:db8::/64"},
+					},
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "Neighbor with both address and interface",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:       65002,
+											Address:   "192.0.2.2",
+											Interface: "swp1",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("failed to process neighbor 65002@swp1 for router 65001-: address and interface are mutually exclusive"),
+		},
+		{
+			name: "Neighbor with both asn and dynamicASN",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:        65002,
+											DynamicASN: v1beta1.InternalASNMode,
+											Interface:  "swp1",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("failed to process neighbor internal@swp1 for router 65001-: asn and dynamicASN are mutually exclusive"),
+		},
+		{
+			name: "Neighbor template defined in another config, shared by the same neighbor in multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							BFDProfiles: []v1beta1.BFDProfile{
+								{
+									Name: "bfd1",
+								},
+							},
+							NeighborTemplates: []v1beta1.NeighborTemplate{
+								{
+									Name:          "tor",
+									HoldTime:      metav1.Duration{Duration: 90 * time.Second},
+									KeepaliveTime: metav1.Duration{Duration: 30 * time.Second},
+									EBGPMultiHop:  true,
+									BFDProfile:    "bfd1",
+									ToAdvertise: v1beta1.Advertise{
+										Allowed: v1beta1.AllowedPrefixes{
+											Mode: v1beta1.AllowAll,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:      65002,
+											Address:  "192.0.2.2",
+											Template: "tor",
+										},
+									},
+									Prefixes: []string{"192.0.2.0/24"},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65002,
+											Address: "192.0.2.2",
+											ToReceive: v1beta1.Receive{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.5.0/24"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65001,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily:      ipfamily.IPv4,
+								Name:          "65002@192.0.2.2",
+								ASN:           65002,
+								Addr:          "192.0.2.2",
+								HoldTime:      90,
+								KeepaliveTime: 30,
+								EBGPMultiHop:  true,
+								BFDProfile:    "bfd1",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "192.0.2.0/24",
+										},
+									},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "192.0.5.0/24",
+										},
+									},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						IPV4Prefixes: []string{"192.0.2.0/24"},
+						IPV6Prefixes: []string{},
+					},
+				},
+				BFDProfiles: []frr.BFDProfile{
+					{
+						Name: "bfd1",
+					},
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "Neighbor with non existing template",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:      65002,
+											Address:  "192.0.2.2",
+											Template: "tor",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("neighbor 65002@192.0.2.2 at vrf  references non existing template tor"),
+		},
+		{
+			name: "Conflicting neighbor templates from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							NeighborTemplates: []v1beta1.NeighborTemplate{
+								{
+									Name:         "tor",
+									EBGPMultiHop: true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							NeighborTemplates: []v1beta1.NeighborTemplate{
+								{
+									Name: "tor",
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("multiple neighbor templates specified for tor with different values"),
+		},
+		{
+			name: "Extended communities from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65002,
+											Address: "192.0.2.2",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.3.0/24"},
+												},
+												PrefixesWithCommunity: []v1beta1.CommunityPrefixes{
+													{
+														Prefixes:  []string{"192.0.3.0/24"},
+														Community: "rt:65001:100",
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.3.0/24"},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65002,
+											Address: "192.0.2.2",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.3.0/24"},
+												},
+												PrefixesWithCommunity: []v1beta1.CommunityPrefixes{
+													{
+														Prefixes:  []string{"192.0.3.0/24"},
+														Community: "soo:192.0.2.1:10",
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.3.0/24"},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65001,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily: ipfamily.IPv4,
+								Name:     "65002@192.0.2.2",
+								ASN:      65002,
+								Addr:     "192.0.2.2",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{
+										{
+											IPFamily:            ipfamily.IPv4,
+											Prefix:              "192.0.3.0/24",
+											ExtendedCommunities: []string{"rt:65001:100", "soo:192.0.2.1:10"},
+										},
+									},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						IPV4Prefixes: []string{"192.0.3.0/24"},
+						IPV6Prefixes: []string{},
+					},
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "Invalid extended community",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65002,
+											Address: "192.0.2.2",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.3.0/24"},
+												},
+												PrefixesWithCommunity: []v1beta1.CommunityPrefixes{
+													{
+														Prefixes:  []string{"192.0.3.0/24"},
+														Community: "rt:4200000000:70000",
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.3.0/24"},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("failed to process neighbor 65002@192.0.2.2 for router 65001-: invalid community rt:4200000000:70000"),
+		},
+		{
+			name: "Link bandwidth extended community with an administrator other than the router asn",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65002,
+											Address: "192.0.2.2",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.3.0/24"},
+												},
+												PrefixesWithCommunity: []v1beta1.CommunityPrefixes{
+													{
+														Prefixes:  []string{"192.0.3.0/24"},
+														Community: "lb:65000:1000",
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.3.0/24"},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("failed to process neighbor 65002@192.0.2.2 for router 65001-: invalid community value: administrator 65000 of link bandwidth community \"lb:65000:1000\" must be the local AS number 65001"),
+		},
+		{
+			name: "Dynamic neighbors from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									DynamicNeighbors: []v1beta1.DynamicNeighbor{
+										{
+											ListenRange:  "192.0.2.0/24",
+											ASN:          65002,
+											SessionLimit: 20,
+										},
+									},
+									Prefixes: []string{"192.0.3.0/24"},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									DynamicNeighbors: []v1beta1.DynamicNeighbor{
+										{
+											ListenRange:  "192.0.2.0/24",
+											ASN:          65002,
+											SessionLimit: 20,
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Mode: v1beta1.AllowAll,
+												},
+											},
+										},
+										{
+											ListenRange: "2001:db8::/64",
+											DynamicASN:  v1beta1.ExternalASNMode,
+										},
+									},
+									Prefixes: []string{"192.0.3.0/24"},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN:       65001,
+						ListenLimit: 120,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily:     ipfamily.IPv4,
+								Name:         "65002@192.0.2.0/24",
+								ASN:          65002,
+								ListenRange:  "192.0.2.0/24",
+								SessionLimit: 20,
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "192.0.3.0/24",
+										},
+									},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+							{
+								IPFamily:    ipfamily.IPv6,
+								Name:        "external@2001:db8::/64",
+								DynamicASN:  "external",
+								ListenRange: "2001:db8::/64",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						IPV4Prefixes: []string{"192.0.3.0/24"},
+						IPV6Prefixes: []string{},
+					},
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "Dynamic neighbors with overlapping ranges",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									DynamicNeighbors: []v1beta1.DynamicNeighbor{
+										{
+											ListenRange: "192.0.2.0/24",
+											ASN:         65002,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									DynamicNeighbors: []v1beta1.DynamicNeighbor{
+										{
+											ListenRange: "192.0.2.128/25",
+											ASN:         65003,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("listen range 192.0.2.128/25 overlaps with 192.0.2.0/24 in router 65001-"),
+		},
+		{
+			name: "Dynamic neighbor with host bits set in the listen range",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									DynamicNeighbors: []v1beta1.DynamicNeighbor{
+										{
+											ListenRange: "192.0.2.1/24",
+											ASN:         65002,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("failed to process dynamic neighbor 65002@192.0.2.1/24 for router 65001-: invalid listen range 192.0.2.1/24: host bits must not be set, expected 192.0.2.0/24"),
+		},
+		{
+			name: "Neighbor with MED and as-path prepend, merged from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									ID:  "192.0.2.20",
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.2.0/24", "2001:db8::/64"},
+												},
+												PrefixesWithMED: []v1beta1.MEDPrefixes{
+													{
+														Prefixes: []string{"192.0.2.0/24", "2001:db8::/64"},
+														MED:      50,
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.2.0/24", "2001:db8::/64"},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									ID:  "192.0.2.20",
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.2.0/24"},
+												},
+												PrefixesWithASPathPrepend: []v1beta1.ASPathPrependPrefixes{
+													{
+														Prefixes: []string{"192.0.2.0/24"},
+														LastAS:   true,
+														Count:    2,
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.2.0/24"},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN:    65040,
+						RouterID: "192.0.2.20",
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily: ipfamily.IPv4,
+								Name:     "65041@192.0.2.21",
+								ASN:      65041,
+								Addr:     "192.0.2.21",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{
+										{
+											IPFamily:      ipfamily.IPv4,
+											Prefix:        "192.0.2.0/24",
+											MED:           pointer.Uint32(50),
+											ASPathPrepend: &frr.ASPathPrepend{LastAS: true, Count: 2},
+										},
+									},
+									PrefixesV6: []frr.OutgoingFilter{
+										{
+											IPFamily: ipfamily.IPv6,
+											Prefix:   "2001:db8::/64",
+											MED:      pointer.Uint32(50),
+										},
+									},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						IPV4Prefixes: []string{"192.0.2.0/24"},
+						IPV6Prefixes: []string{"2001:db8::/64"},
+					},
+				},
+			},
+		},
+		{
+			name: "Neighbor with different MEDs for the same prefix in multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.2.0/24"},
+												},
+												PrefixesWithMED: []v1beta1.MEDPrefixes{
+													{
+														Prefixes: []string{"192.0.2.0/24"},
+														MED:      50,
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.2.0/24"},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.2.0/24"},
+												},
+												PrefixesWithMED: []v1beta1.MEDPrefixes{
+													{
+														Prefixes: []string{"192.0.2.0/24"},
+														MED:      60,
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.2.0/24"},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("multiple meds (50 != 60) specified for prefix 192.0.2.0/24"),
+		},
+		{
+			name: "Neighbor with as-path prepend setting both asn and lastAS",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.2.0/24"},
+												},
+												PrefixesWithASPathPrepend: []v1beta1.ASPathPrependPrefixes{
+													{
+														Prefixes: []string{"192.0.2.0/24"},
+														ASN:      65040,
+														LastAS:   true,
+														Count:    2,
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.2.0/24"},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("asn and lastAS are mutually exclusive in as-path prepend"),
+		},
+		{
+			name: "Neighbor with receive policies, merged from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToReceive: v1beta1.Receive{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"0.0.0.0/0"},
+												},
+												Policies: []v1beta1.ReceivePolicy{
+													{
+														Prefixes:  []string{"0.0.0.0/0", "::/0"},
+														LocalPref: pointer.Uint32(200),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToReceive: v1beta1.Receive{
+												Allowed: v1beta1.AllowedPrefixes{
+													Mode: v1beta1.AllowAll,
+												},
+												Policies: []v1beta1.ReceivePolicy{
+													{
+														Prefixes:  []string{"0.0.0.0/0", "::/0"},
+														LocalPref: pointer.Uint32(200),
+													},
+													{
+														Communities:      []string{"no-export", "large:65040:1:1"},
+														Weight:           pointer.Uint32(100),
+														SetCommunities:   []string{"65040:10"},
+														StripCommunities: true,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65040,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily: ipfamily.IPv4,
+								Name:     "65041@192.0.2.21",
+								ASN:      65041,
+								Addr:     "192.0.2.21",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									All:        true,
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+									Policies: []frr.IncomingPolicy{
+										{
+											PrefixesV4: []string{"0.0.0.0/0"},
+											PrefixesV6: []string{"::/0"},
+											LocalPref:  pointer.Uint32(200),
+										},
+										{
+											Communities:      []string{"65535:65281"},
+											LargeCommunities: []string{"65040:1:1"},
+											Weight:           pointer.Uint32(100),
+											SetCommunities:   []string{"65040:10"},
+											StripCommunities: true,
+										},
+									},
+								},
+							},
+						},
+						IPV4Prefixes: []string{},
+						IPV6Prefixes: []string{},
+					},
+				},
+			},
+		},
+		{
+			name: "Neighbor with receive policy without actions",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToReceive: v1beta1.Receive{
+												Policies: []v1beta1.ReceivePolicy{
+													{
+														Prefixes: []string{"0.0.0.0/0"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("receive policy must specify at least one action"),
+		},
+		{
+			name: "Neighbor with receive policy matching an extended community",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToReceive: v1beta1.Receive{
+												Policies: []v1beta1.ReceivePolicy{
+													{
+														Communities: []string{"rt:65040:100"},
+														LocalPref:   pointer.Uint32(50),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("extended community rt:65040:100 can't be used in receive policies"),
+		},
+		{
+			name: "Neighbor with prefix ranges, merged from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"192.0.2.0/24"},
+													Ranges: []v1beta1.PrefixRange{
+														{Prefix: "192.0.3.0/24", LE: 32, Action: v1beta1.DenyPrefix},
+														{Prefix: "192.0.0.0/16", LE: 32},
+													},
+												},
+											},
+											ToReceive: v1beta1.Receive{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"10.2.0.0/16"},
+													Ranges: []v1beta1.PrefixRange{
+														{Prefix: "10.1.0.0/16", LE: 32},
+														{Prefix: "10.0.0.0/8", GE: 16, LE: 32, Action: v1beta1.DenyPrefix},
+													},
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.2.0/24", "192.0.3.0/24", "192.0.4.0/24", "2001:db8::/64"},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToReceive: v1beta1.Receive{
+												Allowed: v1beta1.AllowedPrefixes{
+													Ranges: []v1beta1.PrefixRange{
+														{Prefix: "10.0.0.0/8", GE: 16, LE: 32, Action: v1beta1.DenyPrefix},
+														{Prefix: "10.0.0.0/8", LE: 32},
+														{Prefix: "2001:db8::/32", GE: 64},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65040,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily: ipfamily.IPv4,
+								Name:     "65041@192.0.2.21",
+								ASN:      65041,
+								Addr:     "192.0.2.21",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "192.0.2.0/24",
+										},
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "192.0.4.0/24",
+										},
+									},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "10.2.0.0/16",
+										},
+									},
+									PrefixesV6: []frr.IncomingFilter{},
+									RangesV4: []frr.IncomingFilter{
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "10.1.0.0/16",
+											LE:       32,
+										},
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "10.0.0.0/8",
+											GE:       16,
+											LE:       32,
+											Deny:     true,
+										},
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "10.0.0.0/8",
+											LE:       32,
+										},
+									},
+									RangesV6: []frr.IncomingFilter{
+										{
+											IPFamily: ipfamily.IPv6,
+											Prefix:   "2001:db8::/32",
+											GE:       64,
+										},
+									},
+								},
+							},
+						},
+						IPV4Prefixes: []string{"192.0.2.0/24", "192.0.3.0/24", "192.0.4.0/24"},
+						IPV6Prefixes: []string{"2001:db8::/64"},
+					},
+				},
+			},
+		},
+		{
+			name: "Neighbor with invalid prefix range",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											ToReceive: v1beta1.Receive{
+												Allowed: v1beta1.AllowedPrefixes{
+													Ranges: []v1beta1.PrefixRange{
+														{Prefix: "10.0.0.0/8", GE: 24, LE: 16},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("invalid prefix range 10.0.0.0/8: ge 24 is greater than le 16"),
+		},
+		{
+			name: "Neighbor with prefix ranges, merged with the ones of its template",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							NeighborTemplates: []v1beta1.NeighborTemplate{
+								{
+									Name: "tor",
+									ToReceive: v1beta1.Receive{
+										Allowed: v1beta1.AllowedPrefixes{
+											Ranges: []v1beta1.PrefixRange{
+												{Prefix: "10.0.0.0/8", GE: 16, LE: 32, Action: v1beta1.DenyPrefix},
+											},
+										},
+									},
+								},
+							},
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:      65041,
+											Address:  "192.0.2.21",
+											Template: "tor",
+											ToReceive: v1beta1.Receive{
+												Allowed: v1beta1.AllowedPrefixes{
+													Ranges: []v1beta1.PrefixRange{
+														{Prefix: "10.0.0.0/8", LE: 32},
+														{Prefix: "2001:db8::/32", GE: 64},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65040,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily: ipfamily.IPv4,
+								Name:     "65041@192.0.2.21",
+								ASN:      65041,
+								Addr:     "192.0.2.21",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+									RangesV4: []frr.IncomingFilter{
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "10.0.0.0/8",
+											GE:       16,
+											LE:       32,
+											Deny:     true,
+										},
+										{
+											IPFamily: ipfamily.IPv4,
+											Prefix:   "10.0.0.0/8",
+											LE:       32,
+										},
+									},
+									RangesV6: []frr.IncomingFilter{
+										{
+											IPFamily: ipfamily.IPv6,
+											Prefix:   "2001:db8::/32",
+											GE:       64,
+										},
+									},
+								},
+							},
+						},
+						IPV4Prefixes: []string{},
+						IPV6Prefixes: []string{},
+					},
+				},
+			},
+		},
+		{
+			name: "Neighbor with max prefixes",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											MaxPrefixes: v1beta1.MaxPrefixes{
+												IPv4: &v1beta1.PrefixLimit{
+													Limit:               1000,
+													ThresholdPercentage: 80,
+													RestartInterval:     metav1.Duration{Duration: 5 * time.Minute},
+												},
+												IPv6: &v1beta1.PrefixLimit{
+													Limit:       100,
+													WarningOnly: true,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65040,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily: ipfamily.IPv4,
+								Name:     "65041@192.0.2.21",
+								ASN:      65041,
+								Addr:     "192.0.2.21",
+								MaxPrefixesV4: &frr.MaxPrefixes{
+									Limit:          1000,
+									Threshold:      80,
+									RestartMinutes: 5,
+								},
+								MaxPrefixesV6: &frr.MaxPrefixes{
+									Limit:       100,
+									WarningOnly: true,
+								},
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						IPV4Prefixes: []string{},
+						IPV6Prefixes: []string{},
+					},
+				},
+			},
+		},
+		{
+			name: "Neighbor with max prefixes restarting after a fraction of minute",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											MaxPrefixes: v1beta1.MaxPrefixes{
+												IPv4: &v1beta1.PrefixLimit{
+													Limit:           1000,
+													RestartInterval: metav1.Duration{Duration: 90 * time.Second},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("invalid ipv4 max prefixes: restart interval 1m30s must be a whole number of minutes"),
+		},
+		{
+			name: "Same neighbor with different max prefixes in multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+											MaxPrefixes: v1beta1.MaxPrefixes{
+												IPv4: &v1beta1.PrefixLimit{Limit: 1000},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65041,
+											Address: "192.0.2.21",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("multiple max prefixes specified for neighbor 192.0.2.21 at vrf "),
+		},
+		{
+			name: "Router with graceful restart, merged with a router without it",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:             65041,
+											Address:         "192.0.2.21",
+											GracefulRestart: v1beta1.GracefulRestartHelper,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									GracefulRestart: &v1beta1.GracefulRestart{
+										Mode:               v1beta1.GracefulRestartRestart,
+										RestartTime:        metav1.Duration{Duration: time.Minute},
+										LongLivedStaleTime: metav1.Duration{Duration: time.Hour},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65040,
+						GracefulRestart: &frr.GracefulRestart{
+							Mode:          "restart",
+							RestartTime:   60,
+							LLGRStaleTime: 3600,
+						},
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily:        ipfamily.IPv4,
+								Name:            "65041@192.0.2.21",
+								ASN:             65041,
+								Addr:            "192.0.2.21",
+								GracefulRestart: "helper",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						IPV4Prefixes: []string{},
+						IPV6Prefixes: []string{},
+					},
+				},
+			},
+		},
+		{
+			name: "Router with different graceful restart configurations",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									GracefulRestart: &v1beta1.GracefulRestart{
+										Mode: v1beta1.GracefulRestartRestart,
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									GracefulRestart: &v1beta1.GracefulRestart{
+										Mode: v1beta1.GracefulRestartDisabled,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("different graceful restart configurations specified for same vrf: "),
+		},
+		{
+			name: "Router with imports and vpn leaking from multiple configurations",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									VRF: "red",
+									Imports: []v1beta1.Import{
+										{
+											VRF: "default",
+											Prefixes: []v1beta1.PrefixRange{
+												{Prefix: "10.0.0.0/8", LE: 24},
+											},
+										},
+										{VRF: "blue"},
+									},
+									VPN: &v1beta1.VPNLeak{
+										RouteDistinguisher: "65040:1",
+										ExportRouteTargets: []string{"65040:100"},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									VRF: "red",
+									Imports: []v1beta1.Import{
+										{
+											VRF: "default",
+											Prefixes: []v1beta1.PrefixRange{
+												{Prefix: "10.0.0.0/8", LE: 24},
+												{Prefix: "fc00:f853:ccd:e799::/64", Action: v1beta1.DenyPrefix},
+											},
+										},
+										{
+											VRF: "blue",
+											Prefixes: []v1beta1.PrefixRange{
+												{Prefix: "192.168.10.0/24"},
+											},
+										},
+									},
+									VPN: &v1beta1.VPNLeak{
+										ImportRouteTargets: []string{"65040:200", "192.0.2.1:10"},
+										ExportRouteTargets: []string{"65040:100", "65040:101"},
+										RouteDistinguisher: "65040:1",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN:     65040,
+						VRF:       "red",
+						Neighbors: []*frr.NeighborConfig{},
+						Imports: []frr.VRFImport{
+							{
+								VRF:        "blue",
+								All:        true,
+								PrefixesV4: []frr.IncomingFilter{},
+								PrefixesV6: []frr.IncomingFilter{},
+							},
+							{
+								VRF: "default",
+								PrefixesV4: []frr.IncomingFilter{
+									{IPFamily: ipfamily.IPv4, Prefix: "10.0.0.0/8", LE: 24},
+								},
+								PrefixesV6: []frr.IncomingFilter{
+									{IPFamily: ipfamily.IPv6, Prefix: "fc00:f853:ccd:e799::/64", Deny: true},
+								},
+							},
+						},
+						VPN: &frr.VPNLeak{
+							RouteDistinguisher: "65040:1",
+							ImportRouteTargets: []string{"192.0.2.1:10", "65040:200"},
+							ExportRouteTargets: []string{"65040:100", "65040:101"},
+						},
+						IPV4Prefixes: []string{},
+						IPV6Prefixes: []string{},
+					},
+				},
+			},
+		},
+		{
+			name: "Router importing from its own vrf",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN:     65040,
+									Imports: []v1beta1.Import{{VRF: "default"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("can't import routes from the router's own vrf default"),
+		},
+		{
+			name: "Router exporting to vpn without route distinguisher",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									VRF: "red",
+									VPN: &v1beta1.VPNLeak{
+										ExportRouteTargets: []string{"65040:100"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("exporting routes to the vpn table requires a route distinguisher"),
+		},
+		{
+			name: "Router with invalid route target",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									VRF: "red",
+									VPN: &v1beta1.VPNLeak{
+										ImportRouteTargets: []string{"65040"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("invalid route target"),
+		},
+		{
+			name: "Router with different route distinguishers",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									VRF: "red",
+									VPN: &v1beta1.VPNLeak{
+										RouteDistinguisher: "65040:1",
+										ExportRouteTargets: []string{"65040:100"},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									VRF: "red",
+									VPN: &v1beta1.VPNLeak{
+										RouteDistinguisher: "65040:2",
+										ExportRouteTargets: []string{"65040:100"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("different route distinguishers (65040:1 != 65040:2) specified for same vrf: red"),
+		},
+		{
+			name: "Routers with evpn",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65040,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:          65041,
+											Address:      "192.0.2.21",
+											ActivateEVPN: true,
+										},
+									},
+									EVPN: &v1beta1.EVPN{
+										AdvertiseAllVNI: true,
+									},
+								},
+								{
+									ASN: 65040,
+									VRF: "red",
+									EVPN: &v1beta1.EVPN{
+										VNI:                100,
+										RouteDistinguisher: "65040:100",
+										ImportRouteTargets: []string{"65040:100", "65040:101"},
+										ExportRouteTargets: []string{"65040:100"},
+										AdvertiseUnicast:   []v1beta1.EVPNUnicastFamily{v1beta1.EVPNUnicastIPv4, v1beta1.EVPNUnicastIPv6},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65040,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily:     ipfamily.IPv4,
+								Name:         "65041@192.0.2.21",
+								ASN:          65041,
+								Addr:         "192.0.2.21",
+								ActivateEVPN: true,
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						EVPN: &frr.EVPN{
+							AdvertiseAllVNI:    true,
+							ImportRouteTargets: []string{},
+							ExportRouteTargets: []string{},
+						},
+						IPV4Prefixes: []string{},
+						IPV6Prefixes: []string{},
+					},
+					{
+						MyASN:     65040,
+						VRF:       "red",
+						Neighbors: []*frr.NeighborConfig{},
+						EVPN: &frr.EVPN{
+							VNI:                  100,
+							RouteDistinguisher:   "65040:100",
+							ImportRouteTargets:   []string{"65040:100", "65040:101"},
+							ExportRouteTargets:   []string{"65040:100"},
+							AdvertiseIPv4Unicast: true,
+							AdvertiseIPv6Unicast: true,
+						},
+						IPV4Prefixes: []string{},
+						IPV6Prefixes: []string{},
+					},
+				},
+			},
+		},
+		{
+			name: "Router of the default vrf with vni",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN:  65040,
+									EVPN: &v1beta1.EVPN{VNI: 100},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("vni can be set only on the routers of non default vrfs"),
+		},
+		{
+			name: "Router of a vrf advertising all vnis",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN:  65040,
+									VRF:  "red",
+									EVPN: &v1beta1.EVPN{AdvertiseAllVNI: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("advertiseAllVNI can be set only on the router of the default vrf"),
+		},
+		{
+			name: "Router with different evpn configurations",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN:  65040,
+									VRF:  "red",
+									EVPN: &v1beta1.EVPN{VNI: 100},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN:  65040,
+									VRF:  "red",
+									EVPN: &v1beta1.EVPN{VNI: 200},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets:  map[string]v1.Secret{},
+			expected: nil,
+			err:      errors.New("different evpn configurations specified for same vrf: red"),
+		},
+		{
+			name: "OSPF routers from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						OSPF: v1beta1.OSPFConfig{
+							Routers: []v1beta1.OSPFRouter{
+								{
+									Areas: []v1beta1.OSPFArea{
+										{
+											ID: "0",
+											Interfaces: []v1beta1.OSPFInterface{
+												{
+													Name: "eth0",
+													Cost: 10,
+													Authentication: &v1beta1.OSPFAuthentication{
+														KeyID:          1,
+														PasswordSecret: v1.SecretReference{Name: "secret1"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						OSPF: v1beta1.OSPFConfig{
+							Routers: []v1beta1.OSPFRouter{
+								{
+									ID: "192.0.2.1",
+									Areas: []v1beta1.OSPFArea{
+										{
+											ID: "0.0.0.0",
+											Interfaces: []v1beta1.OSPFInterface{
+												{
+													Name: "eth0",
+													Cost: 10,
+													Authentication: &v1beta1.OSPFAuthentication{
+														KeyID:          1,
+														PasswordSecret: v1.SecretReference{Name: "secret1"},
+													},
+												},
+											},
+										},
+										{
+											ID: "1",
+											Interfaces: []v1beta1.OSPFInterface{
+												{
+													Name:    "eth1",
+													Passive: true,
+												},
+											},
+										},
+									},
+									Prefixes: []string{"192.0.2.0/24"},
+								},
+								{
+									VRF: "red",
+									Areas: []v1beta1.OSPFArea{
+										{
+											ID: "0.0.0.2",
+											Interfaces: []v1beta1.OSPFInterface{
+												{
+													Name: "eth2",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{
+				"secret1": {
+					Type: v1.SecretTypeBasicAuth,
+					Data: map[string][]byte{
+						"password": []byte("password1"),
+					},
+				},
+			},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{},
+				OSPFRouters: []*frr.OSPFRouterConfig{
+					{
+						RouterID: "192.0.2.1",
+						Interfaces: []frr.OSPFInterface{
+							{
+								Name:      "eth0",
+								Area:      "0.0.0.0",
+								Cost:      10,
+								AuthKeyID: 1,
+								AuthKey:   "password1",
+							},
+							{
+								Name:    "eth1",
+								Area:    "0.0.0.1",
+								Passive: true,
+							},
+						},
+						Prefixes: []string{"192.0.2.0/24"},
+					},
+					{
+						VRF: "red",
+						Interfaces: []frr.OSPFInterface{
+							{
+								Name: "eth2",
+								Area: "0.0.0.2",
+							},
+						},
+						Prefixes: []string{},
+					},
+				},
+			},
+		},
+		{
+			name: "OSPF interface with different settings",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						OSPF: v1beta1.OSPFConfig{
+							Routers: []v1beta1.OSPFRouter{
+								{
+									Areas: []v1beta1.OSPFArea{
+										{ID: "0", Interfaces: []v1beta1.OSPFInterface{{Name: "eth0", Cost: 10}}},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						OSPF: v1beta1.OSPFConfig{
+							Routers: []v1beta1.OSPFRouter{
+								{
+									Areas: []v1beta1.OSPFArea{
+										{ID: "0", Interfaces: []v1beta1.OSPFInterface{{Name: "eth0", Cost: 20}}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("multiple configurations specified for ospf interface eth0"),
+		},
+		{
+			name: "OSPF routers with different router ids",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						OSPF: v1beta1.OSPFConfig{
+							Routers: []v1beta1.OSPFRouter{{ID: "192.0.2.1"}},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						OSPF: v1beta1.OSPFConfig{
+							Routers: []v1beta1.OSPFRouter{{ID: "192.0.2.2"}},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("different ospf router ids (192.0.2.1 != 192.0.2.2) specified for same vrf: "),
+		},
+		{
+			name: "OSPF router redistributing an ipv6 prefix",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						OSPF: v1beta1.OSPFConfig{
+							Routers: []v1beta1.OSPFRouter{{Prefixes: []string{"2001:db8::/64"}}},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("invalid prefix 2001:db8::/64: only ipv4 prefixes can be redistributed"),
+		},
+		{
+			name: "OSPF interface used by routers of different vrfs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						OSPF: v1beta1.OSPFConfig{
+							Routers: []v1beta1.OSPFRouter{
+								{
+									Areas: []v1beta1.OSPFArea{
+										{ID: "0", Interfaces: []v1beta1.OSPFInterface{{Name: "eth0"}}},
+									},
+								},
+								{
+									VRF: "red",
+									Areas: []v1beta1.OSPFArea{
+										{ID: "0", Interfaces: []v1beta1.OSPFInterface{{Name: "eth0"}}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New(`interface eth0 used by the ospf routers of vrfs "" and "red"`),
+		},
+		{
+			name: "Static routes from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						StaticRoutes: []v1beta1.StaticRoutes{
+							{
+								Routes: []v1beta1.StaticRoute{
+									{
+										Prefix:     "192.0.2.0/24",
+										NextHop:    "192.168.1.2",
+										BFDProfile: "bfd1",
+									},
+									{
+										Prefix:    "2001:db8::/64",
+										Blackhole: true,
+										Distance:  200,
+									},
+								},
+							},
+						},
+						BGP: v1beta1.BGPConfig{
+							BFDProfiles: []v1beta1.BFDProfile{
+								{
+									Name: "bfd1",
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						StaticRoutes: []v1beta1.StaticRoutes{
+							{
+								Routes: []v1beta1.StaticRoute{
+									{
+										Prefix:     "192.0.2.0/24",
+										NextHop:    "192.168.1.2",
+										BFDProfile: "bfd1",
+									},
+									{
+										Prefix:    "192.0.3.1/24",
+										Interface: "eth0",
+									},
+								},
+							},
+							{
+								VRF: "red",
+								Routes: []v1beta1.StaticRoute{
+									{
+										Prefix:  "192.0.2.0/24",
+										NextHop: "192.168.2.2",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{},
+				StaticRoutes: []*frr.StaticRoutesConfig{
+					{
+						Routes: []frr.StaticRoute{
+							{
+								IPFamily:   ipfamily.IPv4,
+								Prefix:     "192.0.2.0/24",
+								NextHop:    "192.168.1.2",
+								BFDProfile: "bfd1",
+							},
+							{
+								IPFamily:  ipfamily.IPv4,
+								Prefix:    "192.0.3.0/24",
+								Interface: "eth0",
+							},
+							{
+								IPFamily:  ipfamily.IPv6,
+								Prefix:    "2001:db8::/64",
+								Blackhole: true,
+								Distance:  200,
+							},
+						},
+					},
+					{
+						VRF: "red",
+						Routes: []frr.StaticRoute{
+							{
+								IPFamily: ipfamily.IPv4,
+								Prefix:   "192.0.2.0/24",
+								NextHop:  "192.168.2.2",
+							},
+						},
+					},
+				},
+				BFDProfiles: []frr.BFDProfile{
+					{
+						Name: "bfd1",
+					},
+				},
+			},
+		},
+		{
+			name: "Static routes to the same prefix with different next hops",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						StaticRoutes: []v1beta1.StaticRoutes{
+							{Routes: []v1beta1.StaticRoute{{Prefix: "192.0.2.0/24", NextHop: "192.168.1.2"}}},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						StaticRoutes: []v1beta1.StaticRoutes{
+							{Routes: []v1beta1.StaticRoute{{Prefix: "192.0.2.0/24", NextHop: "192.168.1.3"}}},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("multiple static routes specified for prefix 192.0.2.0/24 at vrf "),
+		},
+		{
+			name: "Blackhole static route with a next hop",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						StaticRoutes: []v1beta1.StaticRoutes{
+							{Routes: []v1beta1.StaticRoute{{Prefix: "192.0.2.0/24", NextHop: "192.168.1.2", Blackhole: true}}},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("a blackhole route can't have a next hop, an interface or a bfd profile"),
+		},
+		{
+			name: "Static route with a next hop of a different family",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						StaticRoutes: []v1beta1.StaticRoutes{
+							{Routes: []v1beta1.StaticRoute{{Prefix: "192.0.2.0/24", NextHop: "2001:db8::1"}}},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("next hop 2001:db8::1 is not of the same family of the prefix"),
+		},
+		{
+			name: "Static route referencing a non existing bfd profile",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						StaticRoutes: []v1beta1.StaticRoutes{
+							{Routes: []v1beta1.StaticRoute{{Prefix: "192.0.2.0/24", NextHop: "192.168.1.2", BFDProfile: "bfd1"}}},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("static route to 192.0.2.0/24 at vrf  references non existing bfd profile bfd1"),
+		},
+		{
+			name: "Router with redistribution from multiple configs",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN:      65001,
+									Prefixes: []string{"192.0.2.0/24"},
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65002,
+											Address: "192.0.2.2",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Mode: v1beta1.AllowAll,
+												},
+											},
+										},
+										{
+											ASN:     65003,
+											Address: "2001:db8::2",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"2001:db8:1::/64"},
+													Ranges: []v1beta1.PrefixRange{
+														{Prefix: "2001:db8::/32", LE: 64},
+													},
+												},
+												PrefixesWithCommunity: []v1beta1.CommunityPrefixes{
+													{Prefixes: []string{"2001:db8:1::/64"}, Community: "10:200"},
+												},
+											},
+										},
+									},
+									Redistribute: v1beta1.Redistribute{
+										IPv4: []v1beta1.Redistribution{
+											{
+												Source:      v1beta1.RedistributeConnected,
+												Prefixes:    []v1beta1.PrefixRange{{Prefix: "10.244.0.0/16", GE: 24}},
+												Communities: []string{"10:100"},
+											},
+										},
+										IPv6: []v1beta1.Redistribution{
+											{
+												Source: v1beta1.RedistributeKernel,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Redistribute: v1beta1.Redistribute{
+										IPv4: []v1beta1.Redistribution{
+											{
+												Source:      v1beta1.RedistributeConnected,
+												Prefixes:    []v1beta1.PrefixRange{{Prefix: "10.245.0.0/16", GE: 24}},
+												Communities: []string{"10:100"},
+											},
+											{
+												Source:    v1beta1.RedistributeTable,
+												Table:     10,
+												LocalPref: 200,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			expected: &frr.Config{
+				Routers: []*frr.RouterConfig{
+					{
+						MyASN: 65001,
+						Neighbors: []*frr.NeighborConfig{
+							{
+								IPFamily: ipfamily.IPv4,
+								Name:     "65002@192.0.2.2",
+								ASN:      65002,
+								Addr:     "192.0.2.2",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{
+										{IPFamily: ipfamily.IPv4, Prefix: "192.0.2.0/24"},
+									},
+									PrefixesV6: []frr.OutgoingFilter{},
+									RedistributedV4: []frr.IncomingFilter{
+										{IPFamily: ipfamily.IPv4, Prefix: "0.0.0.0/0", LE: 32},
+									},
+									RedistributedV6: []frr.IncomingFilter{
+										{IPFamily: ipfamily.IPv6, Prefix: "::/0", LE: 128},
+									},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+							{
+								IPFamily: ipfamily.IPv6,
+								Name:     "65003@2001:db8::2",
+								ASN:      65003,
+								Addr:     "2001:db8::2",
+								Outgoing: frr.AllowedOut{
+									PrefixesV4: []frr.OutgoingFilter{},
+									PrefixesV6: []frr.OutgoingFilter{
+										{IPFamily: ipfamily.IPv6, Prefix: "2001:db8:1::/64", Communities: []string{"10:200"}},
+									},
+									RedistributedV6: []frr.IncomingFilter{
+										{IPFamily: ipfamily.IPv6, Prefix: "2001:db8::/32", LE: 64},
+									},
+								},
+								Incoming: frr.AllowedIn{
+									PrefixesV4: []frr.IncomingFilter{},
+									PrefixesV6: []frr.IncomingFilter{},
+								},
+							},
+						},
+						IPV4Prefixes: []string{"192.0.2.0/24"},
+						IPV6Prefixes: []string{},
+						RedistributeV4: []frr.Redistribution{
+							{
+								IPFamily: ipfamily.IPv4,
+								Source:   "connected",
+								Prefixes: []frr.IncomingFilter{
+									{IPFamily: ipfamily.IPv4, Prefix: "10.244.0.0/16", GE: 24},
+									{IPFamily: ipfamily.IPv4, Prefix: "10.245.0.0/16", GE: 24},
+								},
+								Communities: []string{"10:100"},
+							},
+							{
+								IPFamily:  ipfamily.IPv4,
+								Source:    "table",
+								Table:     10,
+								LocalPref: 200,
+							},
+						},
+						RedistributeV6: []frr.Redistribution{
+							{
+								IPFamily: ipfamily.IPv6,
+								Source:   "kernel",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Redistribution of the same source with different tagging",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Redistribute: v1beta1.Redistribute{
+										IPv4: []v1beta1.Redistribution{{Source: v1beta1.RedistributeConnected, LocalPref: 100}},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Redistribute: v1beta1.Redistribute{
+										IPv4: []v1beta1.Redistribution{{Source: v1beta1.RedistributeConnected, LocalPref: 200}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("different tagging specified for the redistributed ipv4 connected routes at vrf "),
+		},
+		{
+			name: "Redistribution filtered by a prefix of the other family",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Redistribute: v1beta1.Redistribute{
+										IPv4: []v1beta1.Redistribution{
+											{Source: v1beta1.RedistributeKernel, Prefixes: []v1beta1.PrefixRange{{Prefix: "2001:db8::/32", LE: 64}}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("invalid redistribution for router 65001-: invalid ipv4 redistribution of kernel: prefix range 2001:db8::/32 is not of the ipv4 family"),
+		},
+		{
+			name: "Redistribution of a table without the table id",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Redistribute: v1beta1.Redistribute{
+										IPv4: []v1beta1.Redistribution{{Source: v1beta1.RedistributeTable}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("invalid redistribution for router 65001-: invalid ipv4 redistribution of table: table 0 must be between 1 and 65535"),
+		},
+		{
+			name: "Advertising a prefix not redistributed",
+			fromK8s: []v1beta1.FRRConfiguration{
+				{
+					Spec: v1beta1.FRRConfigurationSpec{
+						BGP: v1beta1.BGPConfig{
+							Routers: []v1beta1.Router{
+								{
+									ASN: 65001,
+									Neighbors: []v1beta1.Neighbor{
+										{
+											ASN:     65002,
+											Address: "192.0.2.2",
+											ToAdvertise: v1beta1.Advertise{
+												Allowed: v1beta1.AllowedPrefixes{
+													Prefixes: []string{"10.246.1.0/24"},
+												},
+											},
+										},
+									},
+									Redistribute: v1beta1.Redistribute{
+										IPv4: []v1beta1.Redistribution{
+											{Source: v1beta1.RedistributeConnected, Prefixes: []v1beta1.PrefixRange{{Prefix: "10.244.0.0/16", GE: 24}}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secrets: map[string]v1.Secret{},
+			err:     errors.New("prefix 10.246.1.0/24 advertised to neighbor 192.0.2.2 is not among the prefixes of router 65001-"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			frr, err := apiToFRR(test.fromK8s, clusterResources{passwordSecrets: test.secrets})
+			if test.err != nil && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if test.err == nil && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if diff := cmp.Diff(frr, test.expected); diff != "" {
+				t.Fatalf("config different from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestConversionExcludingInvalid(t *testing.T) {
+	now := time.Now()
+	config := func(name string, created time.Time, routers ...v1beta1.Router) v1beta1.FRRConfiguration {
+		return v1beta1.FRRConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "test-namespace",
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: v1beta1.FRRConfigurationSpec{
+				BGP: v1beta1.BGPConfig{
+					Routers: routers,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		fromK8s          []v1beta1.FRRConfiguration
+		expectedApplied  []string
+		expectedExcluded []string
+	}{
+		{
+			name: "All valid",
+			fromK8s: []v1beta1.FRRConfiguration{
+				config("cfg1", now, v1beta1.Router{ASN: 65001, Prefixes: []string{"192.0.2.0/24"}}),
+				config("cfg2", now, v1beta1.Router{ASN: 65001, Prefixes: []string{"192.0.3.0/24"}}),
+			},
+			expectedApplied:  []string{"cfg1", "cfg2"},
+			expectedExcluded: []string{},
+		},
+		{
+			name: "One invalid",
+			fromK8s: []v1beta1.FRRConfiguration{
+				config("cfg1", now, v1beta1.Router{ASN: 65001, Prefixes: []string{"192.0.2.0/24"}}),
+				config("cfg2", now, v1beta1.Router{ASN: 65001, Prefixes: []string{"192.0.3.0/33"}}),
+			},
+			expectedApplied:  []string{"cfg1"},
+			expectedExcluded: []string{"cfg2"},
+		},
+		{
+			name: "Conflicting, the oldest wins",
+			fromK8s: []v1beta1.FRRConfiguration{
+				config("cfg1", now, v1beta1.Router{ASN: 65001}),
+				config("cfg2", now.Add(-time.Hour), v1beta1.Router{ASN: 65002}),
+				config("cfg3", now.Add(time.Hour), v1beta1.Router{ASN: 65002, VRF: "red"}),
+			},
+			expectedApplied:  []string{"cfg2", "cfg3"},
+			expectedExcluded: []string{"cfg1"},
+		},
+		{
+			name: "Invalid one excluded, bfd profile defined in another configuration",
+			fromK8s: []v1beta1.FRRConfiguration{
+				config("cfg1", now, v1beta1.Router{ASN: 65001, Neighbors: []v1beta1.Neighbor{
+					{ASN: 65002, Address: "192.0.2.1", BFDProfile: "bfd"},
+				}}),
+				func() v1beta1.FRRConfiguration {
+					res := config("cfg2", now)
+					res.Spec.BGP.BFDProfiles = []v1beta1.BFDProfile{{Name: "bfd"}}
+					return res
+				}(),
+				config("cfg3", now, v1beta1.Router{ASN: 65001, Neighbors: []v1beta1.Neighbor{
+					{ASN: 65002, Address: "192.0.2"},
+				}}),
+			},
+			expectedApplied:  []string{"cfg1", "cfg2"},
+			expectedExcluded: []string{"cfg3"},
+		},
+		{
+			name: "Invalid one excluded, advertised prefix declared in another configuration",
+			fromK8s: []v1beta1.FRRConfiguration{
+				config("cfg1", now, v1beta1.Router{ASN: 65001, Neighbors: []v1beta1.Neighbor{
+					{ASN: 65002, Address: "192.0.2.1", ToAdvertise: v1beta1.Advertise{
+						Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"10.0.0.0/24"}},
+					}},
+				}}),
+				config("cfg2", now, v1beta1.Router{ASN: 65001, Prefixes: []string{"10.0.0.0/24"}}),
+				config("cfg3", now, v1beta1.Router{ASN: 65001, Neighbors: []v1beta1.Neighbor{
+					{ASN: 65002, Address: "192.0.2"},
+				}}),
+			},
+			expectedApplied:  []string{"cfg1", "cfg2"},
+			expectedExcluded: []string{"cfg3"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, applied, excluded, err := apiToFRRExcludingInvalid(test.fromK8s, clusterResources{})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			appliedNames := []string{}
+			for _, c := range applied {
+				appliedNames = append(appliedNames, c.Name)
+			}
+			excludedNames := []string{}
+			for _, e := range excluded {
+				excludedNames = append(excludedNames, e.config.Name)
+			}
+			if diff := cmp.Diff(appliedNames, test.expectedApplied); diff != "" {
+				t.Fatalf("applied configurations different from expected: %s", diff)
+			}
+			if diff := cmp.Diff(excludedNames, test.expectedExcluded); diff != "" {
+				t.Fatalf("excluded configurations different from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestConversionSources(t *testing.T) {
+	config := func(name string, routers ...v1beta1.Router) v1beta1.FRRConfiguration {
+		return v1beta1.FRRConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-namespace",
+			},
+			Spec: v1beta1.FRRConfigurationSpec{
+				BGP: v1beta1.BGPConfig{
+					Routers: routers,
+				},
+			},
+		}
+	}
+
+	t.Run("Merged sources", func(t *testing.T) {
+		fromK8s := []v1beta1.FRRConfiguration{
+			config("cfg1", v1beta1.Router{
+				ASN:      65001,
+				Prefixes: []string{"192.0.2.0/24"},
+				Neighbors: []v1beta1.Neighbor{
+					{
+						ASN:         65002,
+						Address:     "192.0.2.1",
+						ToAdvertise: v1beta1.Advertise{Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"192.0.2.0/24"}}},
+					},
+				},
+			}),
+			config("cfg2", v1beta1.Router{
+				ASN:      65001,
+				Prefixes: []string{"192.0.2.0/24", "192.0.3.0/24"},
+				Neighbors: []v1beta1.Neighbor{
+					{
+						ASN:         65002,
+						Address:     "192.0.2.1",
+						ToAdvertise: v1beta1.Advertise{Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"192.0.3.0/24"}}},
+						ToReceive:   v1beta1.Receive{Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"192.0.4.0/24"}}},
+					},
+				},
+			}),
+		}
+
+		res, err := apiToFRR(fromK8s, clusterResources{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		router := res.Routers[0]
+		if diff := cmp.Diff(router.Sources, []string{"test-namespace/cfg1", "test-namespace/cfg2"}); diff != "" {
+			t.Fatalf("router sources different from expected: %s", diff)
+		}
+		expectedPrefixSources := map[string][]string{
+			"192.0.2.0/24": {"test-namespace/cfg1", "test-namespace/cfg2"},
+			"192.0.3.0/24": {"test-namespace/cfg2"},
+		}
+		if diff := cmp.Diff(router.PrefixSources, expectedPrefixSources); diff != "" {
+			t.Fatalf("prefix sources different from expected: %s", diff)
+		}
+		neighbor := router.Neighbors[0]
+		if diff := cmp.Diff(neighbor.Sources, []string{"test-namespace/cfg1", "test-namespace/cfg2"}); diff != "" {
+			t.Fatalf("neighbor sources different from expected: %s", diff)
+		}
+		expectedOutgoing := []frr.OutgoingFilter{
+			{IPFamily: ipfamily.IPv4, Prefix: "192.0.2.0/24", Sources: []string{"test-namespace/cfg1"}},
+			{IPFamily: ipfamily.IPv4, Prefix: "192.0.3.0/24", Sources: []string{"test-namespace/cfg2"}},
+		}
+		if diff := cmp.Diff(neighbor.Outgoing.PrefixesV4, expectedOutgoing); diff != "" {
+			t.Fatalf("outgoing filters different from expected: %s", diff)
+		}
+		expectedIncoming := []frr.IncomingFilter{
+			{IPFamily: ipfamily.IPv4, Prefix: "192.0.4.0/24", Sources: []string{"test-namespace/cfg2"}},
+		}
+		if diff := cmp.Diff(neighbor.Incoming.PrefixesV4, expectedIncoming); diff != "" {
+			t.Fatalf("incoming filters different from expected: %s", diff)
+		}
+	})
+
+	t.Run("Conflict names both sides", func(t *testing.T) {
+		fromK8s := []v1beta1.FRRConfiguration{
+			config("cfg1", v1beta1.Router{
+				ASN:       65001,
+				Neighbors: []v1beta1.Neighbor{{ASN: 65002, Address: "192.0.2.1", Port: 179}},
+			}),
+			config("cfg2", v1beta1.Router{
+				ASN:       65001,
+				Neighbors: []v1beta1.Neighbor{{ASN: 65002, Address: "192.0.2.1", Port: 180}},
+			}),
+		}
+
+		_, err := apiToFRR(fromK8s, clusterResources{})
+		if err == nil {
+			t.Fatalf("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "test-namespace/cfg1") || !strings.Contains(err.Error(), "test-namespace/cfg2") {
+			t.Fatalf("expected error to name both configurations, got %v", err)
+		}
+	})
 }
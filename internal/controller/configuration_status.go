@@ -0,0 +1,91 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	frrk8sv1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+)
+
+// appliedCondition describes the outcome of applying a configuration on the node.
+type appliedCondition struct {
+	status  metav1.ConditionStatus
+	reason  string
+	message string
+}
+
+// updateConfigurationsStatus adds the given node to the failed nodes of the configurations
+// in failed, and removes it from the others. The configurations are updated only when the
+// aggregated status changes, so that a node applying them successfully doesn't write them.
+func updateConfigurationsStatus(ctx context.Context, cli client.Client, all []frrk8sv1beta1.FRRConfiguration,
+	selected, failed sets.Set[string], nodeName string) error {
+	for _, cfg := range all {
+		cfg := cfg
+		key := types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			newStatus := aggregatedStatusFor(cfg.Status, nodeName, selected.Has(key.String()), failed.Has(key.String()))
+			if equality.Semantic.DeepEqual(cfg.Status, newStatus) {
+				return nil
+			}
+			cfg.Status = newStatus
+			err := cli.Status().Update(ctx, &cfg)
+			if err == nil {
+				return nil
+			}
+			if getErr := cli.Get(ctx, key, &cfg); getErr != nil {
+				return getErr
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aggregatedStatusFor returns a copy of the given status with the given node added to
+// or removed from the failed nodes, and the applied condition updated accordingly.
+// The condition is set by the nodes selecting the configuration only.
+func aggregatedStatusFor(status frrk8sv1beta1.FRRConfigurationStatus, nodeName string,
+	isSelected, isFailed bool) frrk8sv1beta1.FRRConfigurationStatus {
+	res := *status.DeepCopy()
+	failedNodes := sets.New(res.FailedNodes...)
+	if isSelected && isFailed {
+		failedNodes.Insert(nodeName)
+	} else {
+		failedNodes.Delete(nodeName)
+	}
+	res.FailedNodes = nil
+	if failedNodes.Len() > 0 {
+		res.FailedNodes = sets.List(failedNodes)
+	}
+
+	if !isSelected && meta.FindStatusCondition(res.Conditions, frrk8sv1beta1.ConditionApplied) == nil {
+		return res
+	}
+
+	cond := metav1.Condition{
+		Type:   frrk8sv1beta1.ConditionApplied,
+		Status: metav1.ConditionTrue,
+		Reason: frrk8sv1beta1.ReasonConfigApplied,
+	}
+	if len(res.FailedNodes) > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = frrk8sv1beta1.ReasonFailedOnNodes
+		cond.Message = fmt.Sprintf("failed on %d nodes, see the FRRNodeState of the failed nodes for the details", len(res.FailedNodes))
+	}
+	meta.SetStatusCondition(&res.Conditions, cond)
+	return res
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAggregatedStatusFor(t *testing.T) {
+	applied := []metav1.Condition{
+		{Type: v1beta1.ConditionApplied, Status: metav1.ConditionTrue, Reason: v1beta1.ReasonConfigApplied},
+	}
+	failedOn := func(message string) []metav1.Condition {
+		return []metav1.Condition{
+			{Type: v1beta1.ConditionApplied, Status: metav1.ConditionFalse, Reason: v1beta1.ReasonFailedOnNodes, Message: message},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		status     v1beta1.FRRConfigurationStatus
+		isSelected bool
+		isFailed   bool
+		expected   v1beta1.FRRConfigurationStatus
+	}{
+		{
+			name:       "Selected, empty status",
+			status:     v1beta1.FRRConfigurationStatus{},
+			isSelected: true,
+			expected:   v1beta1.FRRConfigurationStatus{Conditions: applied},
+		},
+		{
+			name:       "Selected and applied, nothing changes",
+			status:     v1beta1.FRRConfigurationStatus{Conditions: applied},
+			isSelected: true,
+			expected:   v1beta1.FRRConfigurationStatus{Conditions: applied},
+		},
+		{
+			name:       "Selected and failed, the node is added to the failed ones",
+			status:     v1beta1.FRRConfigurationStatus{Conditions: failedOn("failed on 1 nodes, see the FRRNodeState of the failed nodes for the details"), FailedNodes: []string{"node2"}},
+			isSelected: true,
+			isFailed:   true,
+			expected: v1beta1.FRRConfigurationStatus{
+				Conditions:  failedOn("failed on 2 nodes, see the FRRNodeState of the failed nodes for the details"),
+				FailedNodes: []string{"node1", "node2"},
+			},
+		},
+		{
+			name:       "Selected and recovered, the condition is updated",
+			status:     v1beta1.FRRConfigurationStatus{Conditions: failedOn("failed on 1 nodes, see the FRRNodeState of the failed nodes for the details"), FailedNodes: []string{"node1"}},
+			isSelected: true,
+			expected:   v1beta1.FRRConfigurationStatus{Conditions: applied},
+		},
+		{
+			name:       "Not selected, empty status",
+			status:     v1beta1.FRRConfigurationStatus{},
+			isSelected: false,
+			expected:   v1beta1.FRRConfigurationStatus{},
+		},
+		{
+			name:       "Not selected anymore, the node is removed from the failed ones",
+			status:     v1beta1.FRRConfigurationStatus{Conditions: failedOn("failed on 2 nodes, see the FRRNodeState of the failed nodes for the details"), FailedNodes: []string{"node1", "node2"}},
+			isSelected: false,
+			expected: v1beta1.FRRConfigurationStatus{
+				Conditions:  failedOn("failed on 1 nodes, see the FRRNodeState of the failed nodes for the details"),
+				FailedNodes: []string{"node2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := aggregatedStatusFor(test.status, "node1", test.isSelected, test.isFailed)
+			if diff := cmp.Diff(res, test.expected, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Fatalf("status different from expected: %s", diff)
+			}
+		})
+	}
+}
@@ -74,6 +74,13 @@ func (f *fakeFRR) ApplyConfig(config *frr.Config) error {
 	return nil
 }
 
+func (f *fakeFRR) GetStatus() frr.Status {
+	return frr.Status{
+		LastReloadResult: "success",
+		RunningConfig:    "running",
+	}
+}
+
 func TestAPIs(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode.")
@@ -111,13 +118,28 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).ToNot(HaveOccurred())
 
-	err = (&FRRConfigurationReconciler{
-		Client:     k8sManager.GetClient(),
-		Scheme:     k8sManager.GetScheme(),
-		FRRHandler: &localFRR,
-		Logger:     log.NewNopLogger(),
-		NodeName:   testNodeName,
-		Namespace:  testNamespace,
+	nodeStateUpdates, notifyNodeState := NewNodeStateNotifier(testNodeName)
+	configReconciler := &FRRConfigurationReconciler{
+		Client:        k8sManager.GetClient(),
+		Scheme:        k8sManager.GetScheme(),
+		FRRHandler:    &localFRR,
+		Logger:        log.NewNopLogger(),
+		NodeName:      testNodeName,
+		Namespace:     testNamespace,
+		StatusChanged: notifyNodeState,
+		Recorder:      k8sManager.GetEventRecorderFor("frr-k8s"),
+	}
+	err = configReconciler.SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&FRRNodeStateReconciler{
+		Client:           k8sManager.GetClient(),
+		Scheme:           k8sManager.GetScheme(),
+		FRRStatus:        &localFRR,
+		ConversionStatus: configReconciler,
+		Logger:           log.NewNopLogger(),
+		NodeName:         testNodeName,
+		Update:           nodeStateUpdates,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -196,6 +218,7 @@ var _ = Describe("Frrk8s controller", func() {
 					Routers: []*frr.RouterConfig{{MyASN: uint32(42),
 						IPV4Prefixes: []string{},
 						IPV6Prefixes: []string{},
+						Sources:      []string{"default/test"},
 						Neighbors:    []*frr.NeighborConfig{},
 					}},
 				},
@@ -227,6 +250,7 @@ var _ = Describe("Frrk8s controller", func() {
 					Routers: []*frr.RouterConfig{{MyASN: uint32(42),
 						IPV4Prefixes: []string{},
 						IPV6Prefixes: []string{},
+						Sources:      []string{"default/test"},
 						Neighbors:    []*frr.NeighborConfig{},
 					}},
 				},
@@ -242,9 +266,11 @@ var _ = Describe("Frrk8s controller", func() {
 			}).Should(Equal(
 				&frr.Config{
 					Routers: []*frr.RouterConfig{{MyASN: uint32(43),
-						IPV4Prefixes: []string{"192.168.1.0/32"},
-						IPV6Prefixes: []string{},
-						Neighbors:    []*frr.NeighborConfig{},
+						IPV4Prefixes:  []string{"192.168.1.0/32"},
+						IPV6Prefixes:  []string{},
+						Sources:       []string{"default/test"},
+						PrefixSources: map[string][]string{"192.168.1.0/32": {"default/test"}},
+						Neighbors:     []*frr.NeighborConfig{},
 					}},
 				},
 			))
@@ -276,6 +302,7 @@ var _ = Describe("Frrk8s controller", func() {
 					Routers: []*frr.RouterConfig{{MyASN: uint32(42),
 						IPV4Prefixes: []string{},
 						IPV6Prefixes: []string{},
+						Sources:      []string{"default/test"},
 						Neighbors:    []*frr.NeighborConfig{},
 					}},
 				},
@@ -365,6 +392,7 @@ var _ = Describe("Frrk8s controller", func() {
 							MyASN:        uint32(42),
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/no-selector"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 						{
@@ -372,6 +400,7 @@ var _ = Describe("Frrk8s controller", func() {
 							VRF:          "red",
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/with-matching-selector"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 					},
@@ -395,6 +424,7 @@ var _ = Describe("Frrk8s controller", func() {
 							MyASN:        uint32(42),
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/no-selector"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 						{
@@ -402,6 +432,7 @@ var _ = Describe("Frrk8s controller", func() {
 							VRF:          "blue",
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/with-non-matching-selector-at-first"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 						{
@@ -409,6 +440,7 @@ var _ = Describe("Frrk8s controller", func() {
 							VRF:          "red",
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/with-matching-selector"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 					},
@@ -429,6 +461,7 @@ var _ = Describe("Frrk8s controller", func() {
 							MyASN:        uint32(42),
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/no-selector"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 						{
@@ -436,6 +469,7 @@ var _ = Describe("Frrk8s controller", func() {
 							VRF:          "blue",
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/with-non-matching-selector-at-first"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 					},
@@ -494,6 +528,7 @@ var _ = Describe("Frrk8s controller", func() {
 							MyASN:        uint32(42),
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/no-selector"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 					},
@@ -519,6 +554,7 @@ var _ = Describe("Frrk8s controller", func() {
 							MyASN:        uint32(42),
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/no-selector"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 						{
@@ -526,6 +562,7 @@ var _ = Describe("Frrk8s controller", func() {
 							VRF:          "red",
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/with-selector"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 					},
@@ -547,6 +584,7 @@ var _ = Describe("Frrk8s controller", func() {
 							MyASN:        uint32(42),
 							IPV4Prefixes: []string{},
 							IPV6Prefixes: []string{},
+							Sources:      []string{"default/no-selector"},
 							Neighbors:    []*frr.NeighborConfig{},
 						},
 					},
@@ -604,6 +642,7 @@ var _ = Describe("Frrk8s controller", func() {
 					Routers: []*frr.RouterConfig{{MyASN: uint32(42),
 						IPV4Prefixes: []string{},
 						IPV6Prefixes: []string{},
+						Sources:      []string{"default/test"},
 						Neighbors: []*frr.NeighborConfig{
 							{
 								IPFamily: ipfamily.IPv4,
@@ -612,6 +651,7 @@ var _ = Describe("Frrk8s controller", func() {
 								Addr:     "192.0.2.7",
 								Port:     179,
 								Password: "password2",
+								Sources:  []string{"default/test"},
 								Outgoing: frr.AllowedOut{
 									PrefixesV4: []frr.OutgoingFilter{},
 									PrefixesV6: []frr.OutgoingFilter{},
@@ -639,6 +679,7 @@ var _ = Describe("Frrk8s controller", func() {
 					Routers: []*frr.RouterConfig{{MyASN: uint32(42),
 						IPV4Prefixes: []string{},
 						IPV6Prefixes: []string{},
+						Sources:      []string{"default/test"},
 						Neighbors: []*frr.NeighborConfig{
 							{
 								IPFamily: ipfamily.IPv4,
@@ -647,6 +688,7 @@ var _ = Describe("Frrk8s controller", func() {
 								Addr:     "192.0.2.7",
 								Port:     179,
 								Password: "password3",
+								Sources:  []string{"default/test"},
 								Outgoing: frr.AllowedOut{
 									PrefixesV4: []frr.OutgoingFilter{},
 									PrefixesV6: []frr.OutgoingFilter{},
@@ -690,6 +732,7 @@ var _ = Describe("Frrk8s controller", func() {
 					Routers: []*frr.RouterConfig{{MyASN: uint32(42),
 						IPV4Prefixes: []string{},
 						IPV6Prefixes: []string{},
+						Sources:      []string{"default/test"},
 						Neighbors:    []*frr.NeighborConfig{},
 					}},
 					ExtraConfig: "foo\n",
@@ -720,6 +763,7 @@ var _ = Describe("Frrk8s controller", func() {
 					Routers: []*frr.RouterConfig{{MyASN: uint32(42),
 						IPV4Prefixes: []string{},
 						IPV6Prefixes: []string{},
+						Sources:      []string{"default/test"},
 						Neighbors:    []*frr.NeighborConfig{},
 					}},
 					ExtraConfig: "foo\nbar\n",
@@ -737,5 +781,151 @@ var _ = Describe("Frrk8s controller", func() {
 				},
 			))
 		})
+
+		It("should report the status on the node state and on the configurations", func() {
+			frrConfig := &frrk8sv1beta1.FRRConfiguration{
+				ObjectMeta: ctrl.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: frrk8sv1beta1.FRRConfigurationSpec{
+					BGP: frrk8sv1beta1.BGPConfig{
+						Routers: []frrk8sv1beta1.Router{
+							{
+								ASN: uint32(42),
+							},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(context.Background(), frrConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() frrk8sv1beta1.FRRNodeStateStatus {
+				state := &frrk8sv1beta1.FRRNodeState{}
+				err := k8sClient.Get(context.Background(), types.NamespacedName{Name: testNodeName}, state)
+				if err != nil {
+					return frrk8sv1beta1.FRRNodeStateStatus{}
+				}
+				return state.Status
+			}).Should(Equal(frrk8sv1beta1.FRRNodeStateStatus{
+				RunningConfig:          "running",
+				LastReloadResult:       "success",
+				SelectedConfigurations: []string{"default/test"},
+				LastConversionResult:   "success",
+			}))
+
+			Eventually(func() string {
+				cfg := &frrk8sv1beta1.FRRConfiguration{}
+				err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "default"}, cfg)
+				if err != nil || len(cfg.Status.Conditions) != 1 {
+					return ""
+				}
+				return fmt.Sprintf("%v/%s", cfg.Status.FailedNodes, cfg.Status.Conditions[0].Reason)
+			}).Should(Equal("[]/" + frrk8sv1beta1.ReasonConfigApplied))
+
+			err = k8sClient.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "default"}, frrConfig)
+			Expect(err).ToNot(HaveOccurred())
+			frrConfig.Spec.BGP.Routers[0].Prefixes = []string{"foo"}
+			err = k8sClient.Update(context.Background(), frrConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() string {
+				cfg := &frrk8sv1beta1.FRRConfiguration{}
+				err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "default"}, cfg)
+				if err != nil || len(cfg.Status.Conditions) != 1 {
+					return ""
+				}
+				return fmt.Sprintf("%v/%s", cfg.Status.FailedNodes, cfg.Status.Conditions[0].Reason)
+			}).Should(Equal("[" + testNodeName + "]/" + frrk8sv1beta1.ReasonFailedOnNodes))
+
+			Eventually(func() []frrk8sv1beta1.FailedConfiguration {
+				state := &frrk8sv1beta1.FRRNodeState{}
+				err := k8sClient.Get(context.Background(), types.NamespacedName{Name: testNodeName}, state)
+				if err != nil {
+					return nil
+				}
+				return state.Status.FailedConfigurations
+			}).Should(ContainElement(WithTransform(func(f frrk8sv1beta1.FailedConfiguration) string {
+				return f.Name + "/" + f.Reason
+			}, Equal("default/test/"+frrk8sv1beta1.ReasonConfigExcluded))))
+		})
+
+		It("should exclude the invalid configurations and apply the others", func() {
+			valid := &frrk8sv1beta1.FRRConfiguration{
+				ObjectMeta: ctrl.ObjectMeta{
+					Name:      "valid",
+					Namespace: "default",
+				},
+				Spec: frrk8sv1beta1.FRRConfigurationSpec{
+					BGP: frrk8sv1beta1.BGPConfig{
+						Routers: []frrk8sv1beta1.Router{
+							{
+								ASN: uint32(42),
+							},
+						},
+					},
+				},
+			}
+			invalid := &frrk8sv1beta1.FRRConfiguration{
+				ObjectMeta: ctrl.ObjectMeta{
+					Name:      "invalid",
+					Namespace: "default",
+				},
+				Spec: frrk8sv1beta1.FRRConfigurationSpec{
+					BGP: frrk8sv1beta1.BGPConfig{
+						Routers: []frrk8sv1beta1.Router{
+							{
+								ASN:      uint32(42),
+								VRF:      "red",
+								Prefixes: []string{"foo"},
+							},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(context.Background(), valid)
+			Expect(err).ToNot(HaveOccurred())
+			err = k8sClient.Create(context.Background(), invalid)
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() *frr.Config {
+				return localFRR.lastConfig
+			}).Should(Equal(
+				&frr.Config{
+					Routers: []*frr.RouterConfig{{MyASN: uint32(42),
+						IPV4Prefixes: []string{},
+						IPV6Prefixes: []string{},
+						Sources:      []string{"default/valid"},
+						Neighbors:    []*frr.NeighborConfig{},
+					}},
+				},
+			))
+
+			Eventually(func() string {
+				cfg := &frrk8sv1beta1.FRRConfiguration{}
+				err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "invalid", Namespace: "default"}, cfg)
+				if err != nil || len(cfg.Status.Conditions) != 1 {
+					return ""
+				}
+				return fmt.Sprintf("%v/%s", cfg.Status.FailedNodes, cfg.Status.Conditions[0].Reason)
+			}).Should(Equal("[" + testNodeName + "]/" + frrk8sv1beta1.ReasonFailedOnNodes))
+
+			Eventually(func() []frrk8sv1beta1.FailedConfiguration {
+				state := &frrk8sv1beta1.FRRNodeState{}
+				err := k8sClient.Get(context.Background(), types.NamespacedName{Name: testNodeName}, state)
+				if err != nil {
+					return nil
+				}
+				return state.Status.FailedConfigurations
+			}).Should(ContainElement(WithTransform(func(f frrk8sv1beta1.FailedConfiguration) string {
+				return f.Name + "/" + f.Reason
+			}, Equal("default/invalid/"+frrk8sv1beta1.ReasonConfigExcluded))))
+
+			err = k8sClient.Delete(context.Background(), invalid)
+			Expect(err).ToNot(HaveOccurred())
+			err = k8sClient.Delete(context.Background(), valid)
+			Expect(err).ToNot(HaveOccurred())
+		})
 	})
 })
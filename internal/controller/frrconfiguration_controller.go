@@ -18,7 +18,13 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -26,6 +32,8 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,12 +56,37 @@ type FRRConfigurationReconciler struct {
 	Logger     log.Logger
 	NodeName   string
 	Namespace  string
+	// StatusChanged is invoked every time the status returned
+	// by GetConversionStatus changes.
+	StatusChanged func()
+	// Recorder is used to emit events on the configurations
+	// excluded from the node's config.
+	Recorder record.EventRecorder
+
+	statusLock       sync.Mutex
+	conversionStatus ConversionStatus
+	// maintenanceStart is when the node entered maintenance, zero if
+	// the node is not in maintenance.
+	maintenanceStart time.Time
+	// excludedReasons holds the reason why each configuration was excluded
+	// by the last reconciliation, so that the events are emitted only when
+	// it changes.
+	excludedReasons map[string]string
+
+	annotationsLock sync.Mutex
+	// templateAnnotations are the node annotations referenced by the node
+	// templates of the configurations, whose changes must be reconciled.
+	templateAnnotations sets.Set[string]
 }
 
+const conversionSuccess = "success"
+
 // +kubebuilder:rbac:groups=frrk8s.metallb.io,resources=frrconfigurations,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=frrk8s.metallb.io,resources=frrconfigurations/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=frrk8s.metallb.io,resources=frrconfigurations/finalizers,verbs=update
+// +kubebuilder:rbac:groups=frrk8s.metallb.io,resources=communityaliases,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *FRRConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	level.Info(r.Logger).Log("controller", "FRRConfigurationReconciler", "start reconcile", req.NamespacedName.String())
@@ -67,14 +100,19 @@ func (r *FRRConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	}
 
 	level.Debug(r.Logger).Log("controller", "FRRConfigurationReconciler", "k8s config", dumpK8sConfigs(configs))
+	r.setTemplateAnnotations(nodeAnnotationsIn(configs.Items))
 
 	if len(configs.Items) == 0 {
+		r.reportExcluded(nil)
 		err := r.applyEmptyConfig(req)
 		if err != nil {
 			updateErrors.Inc()
 			configStale.Set(1)
+			r.setConversionStatus(nil, nil, err)
+			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, err
+		r.setConversionStatus(nil, nil, nil)
+		return ctrl.Result{}, nil
 	}
 
 	thisNode := &corev1.Node{}
@@ -83,25 +121,50 @@ func (r *FRRConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
-	cfgs, err := configsForNode(configs.Items, thisNode.Labels)
+	valid, excluded := excludeInvalidSelectors(configs.Items)
+	cfgs, err := configsForNode(valid, thisNode.Labels)
 	if err != nil {
+		r.reportExcluded(excluded)
 		updateErrors.Inc()
 		configStale.Set(1)
+		r.setConversionStatus(nil, nil, err)
 		return ctrl.Result{}, err
 	}
+	cfgs, unresolved := resolveNodeTemplatesFor(cfgs, thisNode)
+	excluded = append(excluded, unresolved...)
 
 	secrets, err := r.getSecrets(ctx)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	config, err := apiToFRR(cfgs, secrets)
+	aliases, err := communityAliases(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	resources := clusterResources{
+		passwordSecrets:    secrets,
+		node:               thisNode,
+		interfaceAddresses: interfaceAddresses,
+		communityAliases:   aliases,
+		maintenance:        r.nodeMaintenance(thisNode),
+	}
+	config, applied, notTranslated, err := apiToFRRExcludingInvalid(cfgs, resources)
 	if err != nil {
+		r.reportExcluded(excluded)
 		updateErrors.Inc()
 		configStale.Set(1)
 		level.Error(r.Logger).Log("controller", "FRRConfigurationReconciler", "failed to apply the config", req.NamespacedName.String(), "error", err)
-		return ctrl.Result{}, nil
+		statusErr := r.updateStatus(ctx, configs.Items, cfgs, nil, appliedCondition{
+			status:  metav1.ConditionFalse,
+			reason:  frrk8sv1beta1.ReasonConversionFailed,
+			message: err.Error(),
+		}, err)
+		return ctrl.Result{}, statusErr
 	}
+	excluded = append(excluded, notTranslated...)
+	r.reportExcluded(excluded)
 
 	level.Debug(r.Logger).Log("controller", "FRRConfigurationReconciler", "frr config", dumpFRRConfig(config))
 
@@ -109,18 +172,196 @@ func (r *FRRConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		updateErrors.Inc()
 		configStale.Set(1)
 		level.Error(r.Logger).Log("controller", "FRRConfigurationReconciler", "failed to apply the config", req.NamespacedName.String(), "error", err)
+		statusErr := r.updateStatus(ctx, configs.Items, applied, excluded, appliedCondition{
+			status:  metav1.ConditionFalse,
+			reason:  frrk8sv1beta1.ReasonApplyFailed,
+			message: err.Error(),
+		}, excludedError(excluded))
+		if statusErr != nil {
+			level.Error(r.Logger).Log("controller", "FRRConfigurationReconciler", "failed to update the configurations status", req.NamespacedName.String(), "error", statusErr)
+		}
 		return ctrl.Result{}, err
 	}
 
 	configLoaded.Set(1)
 	configStale.Set(0)
+	err = r.updateStatus(ctx, configs.Items, applied, excluded, appliedCondition{
+		status: metav1.ConditionTrue,
+		reason: frrk8sv1beta1.ReasonConfigApplied,
+	}, excludedError(excluded))
+	if err != nil {
+		level.Error(r.Logger).Log("controller", "FRRConfigurationReconciler", "failed to update the configurations status", req.NamespacedName.String(), "error", err)
+		return ctrl.Result{}, err
+	}
+
+	maintenance, err := maintenanceFor(applied)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// Reconciling again when the prefixes must be withdrawn.
+	if delay, ok := withdrawDelay(maintenance, resources.maintenance); ok {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
 
 	return ctrl.Result{}, nil
 }
 
+// nodeMaintenance returns the maintenance status of the given node, tracking when
+// it entered maintenance. As the time is kept in memory, the delay before withdrawing
+// the prefixes starts again if the daemon restarts during the maintenance.
+func (r *FRRConfigurationReconciler) nodeMaintenance(node *corev1.Node) maintenanceStatus {
+	if !inMaintenance(node) {
+		if !r.maintenanceStart.IsZero() {
+			level.Info(r.Logger).Log("controller", "FRRConfigurationReconciler", "event", "node left maintenance", "node", r.NodeName)
+		}
+		r.maintenanceStart = time.Time{}
+		return maintenanceStatus{}
+	}
+	if r.maintenanceStart.IsZero() {
+		level.Info(r.Logger).Log("controller", "FRRConfigurationReconciler", "event", "node entered maintenance", "node", r.NodeName)
+		r.maintenanceStart = time.Now()
+	}
+	return maintenanceStatus{active: true, elapsed: time.Since(r.maintenanceStart)}
+}
+
+// reportExcluded records the configurations excluded from the config applied to the node
+// in the logs, in the metrics and as events on the configurations themselves. The events
+// are emitted only when a configuration becomes excluded or the reason changes.
+func (r *FRRConfigurationReconciler) reportExcluded(excluded []excludedConfig) {
+	configExcluded.Reset()
+	reasons := map[string]string{}
+	for _, e := range excluded {
+		e := e
+		level.Error(r.Logger).Log("controller", "FRRConfigurationReconciler", "excluded configuration", types.NamespacedName{Namespace: e.config.Namespace, Name: e.config.Name}.String(), "error", e.err)
+		configExcluded.WithLabelValues(e.config.Namespace, e.config.Name).Set(1)
+
+		// The uid distinguishes a configuration deleted and created again.
+		key := fmt.Sprintf("%s/%s/%s", e.config.Namespace, e.config.Name, e.config.UID)
+		reasons[key] = e.err.Error()
+		if prev, ok := r.excludedReasons[key]; ok && prev == reasons[key] {
+			continue
+		}
+		if r.Recorder != nil {
+			r.Recorder.Eventf(&e.config, corev1.EventTypeWarning, frrk8sv1beta1.ReasonConfigExcluded,
+				"excluded from the configuration of node %s: %s", r.NodeName, e.err)
+		}
+	}
+	r.excludedReasons = reasons
+}
+
+// excludedError returns an error describing the excluded configurations, or nil if
+// none was excluded.
+func excludedError(excluded []excludedConfig) error {
+	if len(excluded) == 0 {
+		return nil
+	}
+	msgs := []string{}
+	for _, e := range excluded {
+		msgs = append(msgs, fmt.Sprintf("%s/%s excluded: %s", e.config.Namespace, e.config.Name, e.err))
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// excludeInvalidSelectors splits the given configurations between the ones with a valid
+// nodeSelector and the ones that can't be parsed.
+func excludeInvalidSelectors(cfgs []frrk8sv1beta1.FRRConfiguration) ([]frrk8sv1beta1.FRRConfiguration, []excludedConfig) {
+	valid := []frrk8sv1beta1.FRRConfiguration{}
+	excluded := []excludedConfig{}
+	for _, cfg := range cfgs {
+		_, err := metav1.LabelSelectorAsSelector(&cfg.Spec.NodeSelector)
+		if err != nil {
+			excluded = append(excluded, excludedConfig{
+				config: cfg,
+				err:    fmt.Errorf("could not parse nodeSelector for FRRConfiguration %s/%s, err: %w", cfg.Namespace, cfg.Name, err),
+			})
+			continue
+		}
+		valid = append(valid, cfg)
+	}
+	return valid, excluded
+}
+
+// GetConversionStatus returns the result of the last translation of the
+// configurations selected by the node.
+func (r *FRRConfigurationReconciler) GetConversionStatus() ConversionStatus {
+	r.statusLock.Lock()
+	defer r.statusLock.Unlock()
+	return r.conversionStatus
+}
+
+func (r *FRRConfigurationReconciler) setConversionStatus(selected []frrk8sv1beta1.FRRConfiguration,
+	failures []frrk8sv1beta1.FailedConfiguration, err error) {
+	status := ConversionStatus{
+		LastConversionResult: conversionSuccess,
+		FailedConfigurations: failures,
+	}
+	if err != nil {
+		status.LastConversionResult = err.Error()
+	}
+	for _, cfg := range selected {
+		status.SelectedConfigurations = append(status.SelectedConfigurations, types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}.String())
+	}
+
+	r.statusLock.Lock()
+	changed := !reflect.DeepEqual(r.conversionStatus, status)
+	r.conversionStatus = status
+	r.statusLock.Unlock()
+
+	if changed && r.StatusChanged != nil {
+		r.StatusChanged()
+	}
+}
+
+// updateStatus publishes the outcome of applying the configurations selected by the node
+// with the given condition: the details through the node's FRRNodeState, and the aggregated
+// outcome on the configurations themselves.
+func (r *FRRConfigurationReconciler) updateStatus(ctx context.Context, all, selected []frrk8sv1beta1.FRRConfiguration,
+	excluded []excludedConfig, cond appliedCondition, conversionErr error) error {
+	failures := configurationFailures(selected, excluded, cond)
+	r.setConversionStatus(selected, failures, conversionErr)
+
+	selectedNames := sets.New[string]()
+	for _, cfg := range selected {
+		selectedNames.Insert(types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}.String())
+	}
+	failedNames := sets.New[string]()
+	for _, f := range failures {
+		selectedNames.Insert(f.Name)
+		failedNames.Insert(f.Name)
+	}
+	return updateConfigurationsStatus(ctx, r.Client, all, selectedNames, failedNames, r.NodeName)
+}
+
+// configurationFailures returns the failures of the given selected configurations,
+// if the condition is not successful, and of the excluded ones.
+func configurationFailures(selected []frrk8sv1beta1.FRRConfiguration, excluded []excludedConfig,
+	cond appliedCondition) []frrk8sv1beta1.FailedConfiguration {
+	var res []frrk8sv1beta1.FailedConfiguration
+	if cond.status != metav1.ConditionTrue {
+		for _, cfg := range selected {
+			res = append(res, frrk8sv1beta1.FailedConfiguration{
+				Name:    types.NamespacedName{Namespace: cfg.Namespace, Name: cfg.Name}.String(),
+				Reason:  cond.reason,
+				Message: cond.message,
+			})
+		}
+	}
+	for _, e := range excluded {
+		res = append(res, frrk8sv1beta1.FailedConfiguration{
+			Name:    types.NamespacedName{Namespace: e.config.Namespace, Name: e.config.Name}.String(),
+			Reason:  frrk8sv1beta1.ReasonConfigExcluded,
+			Message: e.err.Error(),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
 func (r *FRRConfigurationReconciler) applyEmptyConfig(req ctrl.Request) error {
 	empty := []frrk8sv1beta1.FRRConfiguration{}
-	config, err := apiToFRR(empty, map[string]corev1.Secret{})
+	config, err := apiToFRR(empty, clusterResources{})
 	if err != nil {
 		level.Error(r.Logger).Log("controller", "FRRConfigurationReconciler", "failed to translate the empty config", req.NamespacedName.String(), "error", err)
 		panic("failed to translate empty config")
@@ -158,7 +399,7 @@ func configsForNode(cfgs []frrk8sv1beta1.FRRConfiguration, nodeLabels map[string
 func (r *FRRConfigurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	p := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return filterNodeEvent(e, r.NodeName)
+			return filterNodeEvent(e, r.NodeName, r.watchedAnnotations())
 		},
 	}
 
@@ -166,6 +407,7 @@ func (r *FRRConfigurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&frrk8sv1beta1.FRRConfiguration{}).
 		Watches(&source.Kind{Type: &corev1.Node{}}, &handler.EnqueueRequestForObject{}).
 		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForObject{}).
+		Watches(&source.Kind{Type: &frrk8sv1beta1.CommunityAlias{}}, &handler.EnqueueRequestForObject{}).
 		WithEventFilter(p).
 		Complete(r)
 }
@@ -187,7 +429,42 @@ func (r *FRRConfigurationReconciler) getSecrets(ctx context.Context) (map[string
 	return secretsMap, nil
 }
 
-func filterNodeEvent(e event.UpdateEvent, thisNode string) bool {
+// communityAliases returns the values of the community aliases, by name.
+func communityAliases(ctx context.Context, cli client.Client) (map[string]string, error) {
+	aliases := frrk8sv1beta1.CommunityAliasList{}
+	err := cli.List(ctx, &aliases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list community aliases: %w", err)
+	}
+	res := map[string]string{}
+	for _, a := range aliases.Items {
+		res[a.Name] = a.Spec.Value
+	}
+	return res, nil
+}
+
+func (r *FRRConfigurationReconciler) setTemplateAnnotations(annotations sets.Set[string]) {
+	r.annotationsLock.Lock()
+	defer r.annotationsLock.Unlock()
+	r.templateAnnotations = annotations
+}
+
+// watchedAnnotations returns the node annotations whose changes must be reconciled:
+// the maintenance one and the ones referenced by the node templates.
+func (r *FRRConfigurationReconciler) watchedAnnotations() sets.Set[string] {
+	r.annotationsLock.Lock()
+	defer r.annotationsLock.Unlock()
+	res := sets.New(frrk8sv1beta1.MaintenanceAnnotation)
+	return res.Union(r.templateAnnotations)
+}
+
+func filterNodeEvent(e event.UpdateEvent, thisNode string, annotations sets.Set[string]) bool {
+	// Ignoring updates to the configurations that don't change their spec
+	// (i.e. the status updates made by the daemons).
+	if _, ok := e.ObjectNew.(*frrk8sv1beta1.FRRConfiguration); ok {
+		return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration()
+	}
+
 	newNodeObj, ok := e.ObjectNew.(*corev1.Node)
 	if !ok {
 		return true
@@ -208,10 +485,27 @@ func filterNodeEvent(e event.UpdateEvent, thisNode string) bool {
 		return false
 	}
 
-	// Ignoring event if it didn't change the node's labels
-	if labels.Equals(labels.Set(oldNodeObj.Labels), labels.Set(newNodeObj.Labels)) {
+	// Ignoring event if it didn't change the node's labels, watched annotations,
+	// addresses, pod CIDRs or maintenance status
+	if labels.Equals(labels.Set(oldNodeObj.Labels), labels.Set(newNodeObj.Labels)) &&
+		annotationsEqual(oldNodeObj.Annotations, newNodeObj.Annotations, annotations) &&
+		reflect.DeepEqual(oldNodeObj.Status.Addresses, newNodeObj.Status.Addresses) &&
+		reflect.DeepEqual(oldNodeObj.Spec.PodCIDRs, newNodeObj.Spec.PodCIDRs) &&
+		inMaintenance(oldNodeObj) == inMaintenance(newNodeObj) {
 		return false
 	}
 
 	return true
 }
+
+// annotationsEqual tells if the given annotations have the same values for the given keys.
+func annotationsEqual(oldAnnotations, newAnnotations map[string]string, keys sets.Set[string]) bool {
+	for k := range keys {
+		oldValue, oldOk := oldAnnotations[k]
+		newValue, newOk := newAnnotations[k]
+		if oldOk != newOk || oldValue != newValue {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kit/log"
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestReportExcluded(t *testing.T) {
+	config := func(name string) v1beta1.FRRConfiguration {
+		return v1beta1.FRRConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &FRRConfigurationReconciler{
+		Logger:   log.NewNopLogger(),
+		NodeName: "node1",
+		Recorder: recorder,
+	}
+
+	steps := []struct {
+		name     string
+		excluded []excludedConfig
+		events   int
+	}{
+		{
+			name:     "Excluded",
+			excluded: []excludedConfig{{config: config("cfg1"), err: errors.New("invalid")}},
+			events:   1,
+		},
+		{
+			name:     "Excluded again for the same reason",
+			excluded: []excludedConfig{{config: config("cfg1"), err: errors.New("invalid")}},
+			events:   0,
+		},
+		{
+			name: "Reason changed and another one excluded",
+			excluded: []excludedConfig{
+				{config: config("cfg1"), err: errors.New("conflicting")},
+				{config: config("cfg2"), err: errors.New("invalid")},
+			},
+			events: 2,
+		},
+		{
+			name:     "Not excluded anymore",
+			excluded: []excludedConfig{{config: config("cfg2"), err: errors.New("invalid")}},
+			events:   0,
+		},
+		{
+			name:     "Excluded again",
+			excluded: []excludedConfig{{config: config("cfg1"), err: errors.New("conflicting")}},
+			events:   1,
+		},
+	}
+
+	for _, s := range steps {
+		r.reportExcluded(s.excluded)
+		if len(recorder.Events) != s.events {
+			t.Fatalf("%s: expected %d events, got %d", s.name, s.events, len(recorder.Events))
+		}
+		for len(recorder.Events) > 0 {
+			<-recorder.Events
+		}
+	}
+}
+
+func TestExcludedConfigDeleted(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &FRRConfigurationReconciler{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+		FRRHandler: &fakeFRR{},
+		Logger:     log.NewNopLogger(),
+		NodeName:   "node1",
+	}
+	r.reportExcluded([]excludedConfig{{
+		config: v1beta1.FRRConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "cfg1", Namespace: "default"}},
+		err:    errors.New("invalid"),
+	}})
+	if count := testutil.CollectAndCount(configExcluded); count != 1 {
+		t.Fatalf("expected the excluded configuration to be exported, got %d series", count)
+	}
+
+	// The configuration is deleted, leaving no configuration at all.
+	_, err := r.Reconcile(context.Background(), ctrl.Request{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if count := testutil.CollectAndCount(configExcluded); count != 0 {
+		t.Fatalf("expected no excluded configuration to be exported, got %d series", count)
+	}
+	if len(r.excludedReasons) != 0 {
+		t.Fatalf("expected no excluded configuration to be tracked, got %v", r.excludedReasons)
+	}
+}
+
+func TestFilterNodeEvent(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node1",
+			Labels:      map[string]string{"rack": "a"},
+			Annotations: map[string]string{"example.com/router-id": "10.0.0.1", "example.com/other": "foo"},
+		},
+	}
+	annotations := sets.New(v1beta1.MaintenanceAnnotation, "example.com/router-id")
+
+	tests := []struct {
+		name     string
+		update   func(n *corev1.Node)
+		expected bool
+	}{
+		{
+			name:     "No changes",
+			update:   func(n *corev1.Node) {},
+			expected: false,
+		},
+		{
+			name:     "Label changed",
+			update:   func(n *corev1.Node) { n.Labels["rack"] = "b" },
+			expected: true,
+		},
+		{
+			name:     "Annotation not referenced changed",
+			update:   func(n *corev1.Node) { n.Annotations["example.com/other"] = "bar" },
+			expected: false,
+		},
+		{
+			name:     "Annotation referenced by a template changed",
+			update:   func(n *corev1.Node) { n.Annotations["example.com/router-id"] = "10.0.0.2" },
+			expected: true,
+		},
+		{
+			name:     "Annotation referenced by a template removed",
+			update:   func(n *corev1.Node) { delete(n.Annotations, "example.com/router-id") },
+			expected: true,
+		},
+		{
+			name:     "Maintenance annotation added",
+			update:   func(n *corev1.Node) { n.Annotations[v1beta1.MaintenanceAnnotation] = "" },
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated := node.DeepCopy()
+			test.update(updated)
+			res := filterNodeEvent(event.UpdateEvent{ObjectOld: node, ObjectNew: updated}, "node1", annotations)
+			if res != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	frrk8sv1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+)
+
+// ConfigValidator validates the FRRConfigurations at admission time, by running
+// the same translation and merge the reconciler runs on each node.
+type ConfigValidator struct {
+	client.Client
+	Logger    log.Logger
+	Namespace string
+}
+
+// Validate returns an error if the given configuration can't be translated
+// to a valid FRR configuration.
+func (v *ConfigValidator) Validate(cfg *frrk8sv1beta1.FRRConfiguration) error {
+	level.Debug(v.Logger).Log("webhook", "frrconfiguration", "action", "validate", "name", cfg.Name, "namespace", cfg.Namespace)
+
+	_, err := metav1.LabelSelectorAsSelector(&cfg.Spec.NodeSelector)
+	if err != nil {
+		return fmt.Errorf("invalid nodeSelector: %w", err)
+	}
+
+	ctx := context.Background()
+	existing := frrk8sv1beta1.FRRConfigurationList{}
+	err = v.List(ctx, &existing)
+	if err != nil {
+		return fmt.Errorf("failed to list existing configurations: %w", err)
+	}
+
+	others := []frrk8sv1beta1.FRRConfiguration{}
+	for _, o := range existing.Items {
+		if o.Namespace == cfg.Namespace && o.Name == cfg.Name {
+			continue
+		}
+		others = append(others, o)
+	}
+
+	secrets, err := v.secretsFor(ctx, append([]frrk8sv1beta1.FRRConfiguration{*cfg}, others...))
+	if err != nil {
+		return err
+	}
+
+	aliases, err := communityAliases(ctx, v.Client)
+	if err != nil {
+		return err
+	}
+
+	// The values depending on the node are not resolved here, as the configuration
+	// may apply to many nodes. They are resolved while simulating the merge.
+	resources := clusterResources{passwordSecrets: secrets, communityAliases: aliases}
+	if _, err := resolveNodeTemplates(*cfg, nil); err != nil {
+		return err
+	}
+
+	nodes := corev1.NodeList{}
+	err = v.List(ctx, &nodes)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	// A configuration not selecting any node yet may be merged with any of the
+	// others later, so it's translated with the definitions of all of them. A
+	// configuration with node templates can be translated only once resolved.
+	selectsNodes, err := selectsAnyNode(cfg, nodes.Items)
+	if err != nil {
+		return err
+	}
+	if !selectsNodes && !hasNodeTemplates(*cfg) {
+		_, err = apiToFRR([]frrk8sv1beta1.FRRConfiguration{*withExternalDefinitions(cfg, others)}, resources)
+		if err != nil {
+			return err
+		}
+	}
+
+	return validateMergeOnNodes(cfg, others, nodes.Items, resources)
+}
+
+// selectsAnyNode tells if the given configuration selects any of the given nodes.
+func selectsAnyNode(cfg *frrk8sv1beta1.FRRConfiguration, nodes []corev1.Node) (bool, error) {
+	for _, node := range nodes {
+		selected, err := configsForNode([]frrk8sv1beta1.FRRConfiguration{*cfg}, node.Labels)
+		if err != nil {
+			return false, err
+		}
+		if len(selected) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validateMergeOnNodes simulates the merge the daemons would perform on each node
+// selected by the given configuration, and returns an error naming the configuration
+// it conflicts with, if any. The given resources are completed with each node.
+func validateMergeOnNodes(cfg *frrk8sv1beta1.FRRConfiguration, others []frrk8sv1beta1.FRRConfiguration,
+	nodes []corev1.Node, resources clusterResources) error {
+	// The configurations with an invalid selector are already failing on every node,
+	// there's nothing we can do for them here.
+	validOthers := []frrk8sv1beta1.FRRConfiguration{}
+	for _, o := range others {
+		if _, err := metav1.LabelSelectorAsSelector(&o.Spec.NodeSelector); err != nil {
+			continue
+		}
+		validOthers = append(validOthers, o)
+	}
+
+	validated := sets.New[string]()
+	for _, node := range nodes {
+		node := node
+		selected, err := configsForNode([]frrk8sv1beta1.FRRConfiguration{*cfg}, node.Labels)
+		if err != nil {
+			return err
+		}
+		if len(selected) == 0 {
+			continue
+		}
+
+		nodeOthers, err := configsForNode(validOthers, node.Labels)
+		if err != nil {
+			return err
+		}
+
+		// Nodes selecting the same set of configurations and resolving the node
+		// templates to the same values produce the same result. The addresses of
+		// the node matter only when the configurations refer to them.
+		nodeCfgs := append([]frrk8sv1beta1.FRRConfiguration{*cfg}, nodeOthers...)
+		key := fmt.Sprintf("%s-%s", configsKey(nodeOthers), nodeTemplatesKey(nodeCfgs, &node))
+		if usesNodeAddresses(nodeCfgs) {
+			key = fmt.Sprintf("%s-%v", key, node.Status.Addresses)
+		}
+		if validated.Has(key) {
+			continue
+		}
+		validated.Insert(key)
+
+		resolved, err := resolveNodeTemplates(*cfg, &node)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the node templates on node %s: %w", node.Name, err)
+		}
+		// The configurations that can't be resolved are already excluded by the node.
+		nodeOthers, _ = resolveNodeTemplatesFor(nodeOthers, &node)
+
+		nodeResources := resources
+		nodeResources.node = &node
+		// Only the definitions of the configurations selecting the same node
+		// are available to the configuration on that node.
+		_, err = apiToFRR([]frrk8sv1beta1.FRRConfiguration{*withExternalDefinitions(&resolved, nodeOthers)}, nodeResources)
+		if err != nil {
+			return fmt.Errorf("invalid configuration on node %s: %w", node.Name, err)
+		}
+		if len(nodeOthers) == 0 {
+			continue
+		}
+		err = conflictsWith(&resolved, nodeOthers, nodeResources)
+		if err != nil {
+			return fmt.Errorf("conflict on node %s: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// nodeTemplatesKey returns a string identifying the values the node templates
+// of the given configurations resolve to on the given node.
+func nodeTemplatesKey(cfgs []frrk8sv1beta1.FRRConfiguration, node *corev1.Node) string {
+	values := []string{}
+	for _, cfg := range cfgs {
+		if !hasNodeTemplates(cfg) {
+			continue
+		}
+		resolved, err := resolveNodeTemplates(cfg, node)
+		if err != nil {
+			values = append(values, fmt.Sprintf("%s/%s-%s", cfg.Namespace, cfg.Name, err))
+			continue
+		}
+		values = append(values, fmt.Sprintf("%s/%s-%v", cfg.Namespace, cfg.Name, resolved.Spec.BGP.Routers))
+	}
+	return strings.Join(values, ",")
+}
+
+// usesNodeAddresses tells if any of the given configurations depends on the
+// addresses of the node, either as a source address or through a node template.
+func usesNodeAddresses(cfgs []frrk8sv1beta1.FRRConfiguration) bool {
+	for _, cfg := range cfgs {
+		for _, r := range cfg.Spec.BGP.Routers {
+			if nodeAddressTemplateRegex.MatchString(r.ID) {
+				return true
+			}
+			for _, p := range r.Prefixes {
+				if nodeAddressTemplateRegex.MatchString(p) {
+					return true
+				}
+			}
+			for _, n := range r.Neighbors {
+				if n.SourceAddressFrom != nil && n.SourceAddressFrom.NodeInternalIP {
+					return true
+				}
+				if nodeAddressTemplateRegex.MatchString(n.Address) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// conflictsWith verifies that the given configuration can be merged with the others.
+// When the merge fails, the configuration whose removal makes the merge succeed is
+// reported as the conflicting one.
+func conflictsWith(cfg *frrk8sv1beta1.FRRConfiguration, others []frrk8sv1beta1.FRRConfiguration, resources clusterResources) error {
+	// The existing configurations may already conflict among themselves, in which
+	// case the daemons exclude some of them. The given one is checked against the
+	// ones the daemons keep, as they would do.
+	_, accepted, _, err := apiToFRRExcludingInvalid(others, resources)
+	if err != nil {
+		return fmt.Errorf("failed to merge the existing configurations: %w", err)
+	}
+
+	_, err = apiToFRR(append([]frrk8sv1beta1.FRRConfiguration{*cfg}, accepted...), resources)
+	if err == nil {
+		return nil
+	}
+
+	for i, o := range accepted {
+		withoutCurrent := []frrk8sv1beta1.FRRConfiguration{*cfg}
+		withoutCurrent = append(withoutCurrent, accepted[:i]...)
+		withoutCurrent = append(withoutCurrent, accepted[i+1:]...)
+		if _, withoutErr := apiToFRR(withoutCurrent, resources); withoutErr == nil {
+			return fmt.Errorf("configuration conflicts with FRRConfiguration %s/%s: %w", o.Namespace, o.Name, err)
+		}
+	}
+
+	return fmt.Errorf("configuration conflicts with FRRConfigurations %s: %w", configsKey(accepted), err)
+}
+
+// configsKey returns a string identifying the given set of configurations.
+func configsKey(cfgs []frrk8sv1beta1.FRRConfiguration) string {
+	names := sets.New[string]()
+	for _, c := range cfgs {
+		names.Insert(c.Namespace + "/" + c.Name)
+	}
+	return strings.Join(sets.List(names), ",")
+}
+
+// secretsFor returns the secrets in the daemon's namespace. The secrets referenced by the
+// configurations that are not there yet are replaced by placeholders, as they may
+// be created after the configurations.
+func (v *ConfigValidator) secretsFor(ctx context.Context, cfgs []frrk8sv1beta1.FRRConfiguration) (map[string]corev1.Secret, error) {
+	secretList := corev1.SecretList{}
+	err := v.List(ctx, &secretList, client.InNamespace(v.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	res := map[string]corev1.Secret{}
+	for _, s := range secretList.Items {
+		res[s.Name] = s
+	}
+
+	for _, cfg := range cfgs {
+		refs := []corev1.SecretReference{}
+		for _, t := range cfg.Spec.BGP.NeighborTemplates {
+			refs = append(refs, t.PasswordSecret)
+		}
+		for _, r := range cfg.Spec.BGP.Routers {
+			for _, n := range r.Neighbors {
+				refs = append(refs, n.PasswordSecret)
+			}
+			for _, d := range r.DynamicNeighbors {
+				refs = append(refs, d.PasswordSecret)
+			}
+		}
+		for _, r := range cfg.Spec.OSPF.Routers {
+			for _, a := range r.Areas {
+				for _, i := range a.Interfaces {
+					if i.Authentication != nil {
+						refs = append(refs, i.Authentication.PasswordSecret)
+					}
+				}
+			}
+		}
+		for _, ref := range refs {
+			name := ref.Name
+			if name == "" {
+				continue
+			}
+			if _, ok := res[name]; ok {
+				continue
+			}
+			// The placeholder must fit the shortest password allowed, the OSPF keys.
+			res[name] = corev1.Secret{
+				Type: corev1.SecretTypeBasicAuth,
+				Data: map[string][]byte{"password": []byte("placeholder")},
+			}
+		}
+	}
+	return res, nil
+}
+
+// withExternalDefinitions returns a copy of the given configuration including the
+// bfd profiles and the neighbor templates defined only in the other configurations,
+// as a neighbor is allowed to reference a profile or a template defined elsewhere.
+// For the same reason, its routers include the prefixes of the other configurations'
+// routers in the same VRF, as a neighbor may advertise them.
+func withExternalDefinitions(cfg *frrk8sv1beta1.FRRConfiguration, others []frrk8sv1beta1.FRRConfiguration) *frrk8sv1beta1.FRRConfiguration {
+	res := cfg.DeepCopy()
+	routerPrefixes := map[string]sets.Set[string]{}
+	for _, r := range res.Spec.BGP.Routers {
+		routerPrefixes[r.VRF] = sets.New(r.Prefixes...)
+	}
+	definedProfiles := map[string]bool{}
+	for _, p := range res.Spec.BGP.BFDProfiles {
+		definedProfiles[p.Name] = true
+	}
+	definedTemplates := map[string]bool{}
+	for _, t := range res.Spec.BGP.NeighborTemplates {
+		definedTemplates[t.Name] = true
+	}
+	for _, o := range others {
+		for _, p := range o.Spec.BGP.BFDProfiles {
+			if definedProfiles[p.Name] {
+				continue
+			}
+			res.Spec.BGP.BFDProfiles = append(res.Spec.BGP.BFDProfiles, p)
+			definedProfiles[p.Name] = true
+		}
+		for _, t := range o.Spec.BGP.NeighborTemplates {
+			if definedTemplates[t.Name] {
+				continue
+			}
+			res.Spec.BGP.NeighborTemplates = append(res.Spec.BGP.NeighborTemplates, t)
+			definedTemplates[t.Name] = true
+		}
+		for _, r := range o.Spec.BGP.Routers {
+			prefixes, ok := routerPrefixes[r.VRF]
+			if !ok {
+				continue
+			}
+			for _, p := range r.Prefixes {
+				// The other configurations are not validated here, their invalid
+				// prefixes must not make this one fail.
+				if ipfamily.ForCIDRString(p) == ipfamily.Unknown {
+					continue
+				}
+				prefixes.Insert(p)
+			}
+		}
+	}
+	for i := range res.Spec.BGP.Routers {
+		r := &res.Spec.BGP.Routers[i]
+		for _, p := range sets.List(routerPrefixes[r.VRF].Delete(r.Prefixes...)) {
+			r.Prefixes = append(r.Prefixes, p)
+		}
+	}
+	return res
+}
@@ -0,0 +1,429 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &v1beta1.FRRConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+		Spec: v1beta1.FRRConfigurationSpec{
+			BGP: v1beta1.BGPConfig{
+				BFDProfiles: []v1beta1.BFDProfile{{Name: "external"}},
+				NeighborTemplates: []v1beta1.NeighborTemplate{{Name: "tor", BFDProfile: "external",
+					PasswordSecret: v1.SecretReference{Name: "tor-password"}}},
+			},
+		},
+	}
+
+	withNeighbor := func(n v1beta1.Neighbor, prefixes ...string) *v1beta1.FRRConfiguration {
+		return &v1beta1.FRRConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: v1beta1.FRRConfigurationSpec{
+				BGP: v1beta1.BGPConfig{
+					Routers: []v1beta1.Router{
+						{
+							ASN:       65001,
+							Neighbors: []v1beta1.Neighbor{n},
+							Prefixes:  prefixes,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"rack": "a"}},
+	}
+	withRouter := func(name string, asn uint32, nodeLabels map[string]string) *v1beta1.FRRConfiguration {
+		return &v1beta1.FRRConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1beta1.FRRConfigurationSpec{
+				BGP: v1beta1.BGPConfig{
+					Routers: []v1beta1.Router{{ASN: asn}},
+				},
+				NodeSelector: metav1.LabelSelector{MatchLabels: nodeLabels},
+			},
+		}
+	}
+
+	torNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"rack": "b", "tor-address": "192.0.2.1", "asn": "65010"}},
+	}
+	podCIDRsNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node3"},
+		Spec:       v1.NodeSpec{PodCIDRs: []string{"10.244.1.0/24"}},
+	}
+	withASNTemplate := func(name string, asn string) *v1beta1.FRRConfiguration {
+		cfg := withRouter(name, 0, nil)
+		cfg.Spec.BGP.Routers[0].ASNTemplate = asn
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		config  *v1beta1.FRRConfiguration
+		objects []client.Object
+		err     bool
+		// errContains is checked only when err is true.
+		errContains string
+	}{
+		{
+			name:   "Valid config",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1"}, "192.0.2.0/24"),
+		},
+		{
+			name:   "Malformed neighbor address",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2"}),
+			err:    true,
+		},
+		{
+			name: "Malformed received prefix",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+				ToReceive: v1beta1.Receive{Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"192.0.2.0/33"}}}}),
+			err: true,
+		},
+		{
+			name: "Invalid community",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+				ToAdvertise: v1beta1.Advertise{
+					Allowed:               v1beta1.AllowedPrefixes{Prefixes: []string{"192.0.2.0/24"}},
+					PrefixesWithCommunity: []v1beta1.CommunityPrefixes{{Prefixes: []string{"192.0.2.0/24"}, Community: "foo"}},
+				}}, "192.0.2.0/24"),
+			err: true,
+		},
+		{
+			name: "Prefix with multiple local prefs",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+				ToAdvertise: v1beta1.Advertise{
+					Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"192.0.2.0/24"}},
+					PrefixesWithLocalPref: []v1beta1.LocalPrefPrefixes{
+						{Prefixes: []string{"192.0.2.0/24"}, LocalPref: 100},
+						{Prefixes: []string{"192.0.2.0/24"}, LocalPref: 200},
+					},
+				}}, "192.0.2.0/24"),
+			err: true,
+		},
+		{
+			name: "Community alias",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+				ToAdvertise: v1beta1.Advertise{
+					Allowed:               v1beta1.AllowedPrefixes{Prefixes: []string{"192.0.2.0/24"}},
+					PrefixesWithCommunity: []v1beta1.CommunityPrefixes{{Prefixes: []string{"192.0.2.0/24"}, Community: "dc-east-anycast"}},
+				}}, "192.0.2.0/24"),
+			objects: []client.Object{&v1beta1.CommunityAlias{
+				ObjectMeta: metav1.ObjectMeta{Name: "dc-east-anycast"},
+				Spec:       v1beta1.CommunityAliasSpec{Value: "large:65001:100:1"},
+			}},
+		},
+		{
+			name: "Unknown community alias",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+				ToAdvertise: v1beta1.Advertise{
+					Allowed:               v1beta1.AllowedPrefixes{Prefixes: []string{"192.0.2.0/24"}},
+					PrefixesWithCommunity: []v1beta1.CommunityPrefixes{{Prefixes: []string{"192.0.2.0/24"}, Community: "dc-east-anycast"}},
+				}}, "192.0.2.0/24"),
+			err:         true,
+			errContains: "unknown community alias dc-east-anycast",
+		},
+		{
+			name: "Community alias with an invalid value",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+				ToAdvertise: v1beta1.Advertise{
+					Allowed:               v1beta1.AllowedPrefixes{Prefixes: []string{"192.0.2.0/24"}},
+					PrefixesWithCommunity: []v1beta1.CommunityPrefixes{{Prefixes: []string{"192.0.2.0/24"}, Community: "dc-east-anycast"}},
+				}}, "192.0.2.0/24"),
+			objects: []client.Object{&v1beta1.CommunityAlias{
+				ObjectMeta: metav1.ObjectMeta{Name: "dc-east-anycast"},
+				Spec:       v1beta1.CommunityAliasSpec{Value: "65001"},
+			}},
+			err: true,
+		},
+		{
+			name:   "Unknown bfd profile",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1", BFDProfile: "external"}),
+			err:    true,
+		},
+		{
+			name:    "Bfd profile defined in another configuration",
+			config:  withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1", BFDProfile: "external"}),
+			objects: []client.Object{existing},
+		},
+		{
+			name:    "Bfd profile defined in another configuration selecting the same node",
+			config:  withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1", BFDProfile: "external"}),
+			objects: []client.Object{node, existing},
+		},
+		{
+			name:   "Bfd profile defined in a configuration selecting other nodes",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1", BFDProfile: "external"}),
+			objects: []client.Object{node, func() *v1beta1.FRRConfiguration {
+				res := existing.DeepCopy()
+				res.Spec.NodeSelector = metav1.LabelSelector{MatchLabels: map[string]string{"rack": "b"}}
+				return res
+			}()},
+			err:         true,
+			errContains: "node1",
+		},
+		{
+			name:   "Unknown neighbor template",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1", Template: "tor"}),
+			err:    true,
+		},
+		{
+			name:    "Neighbor template defined in another configuration",
+			config:  withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1", Template: "tor"}),
+			objects: []client.Object{existing},
+		},
+		{
+			name: "Password secret not created yet",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+				PasswordSecret: v1.SecretReference{Name: "missing"}}),
+		},
+		{
+			name: "Invalid node selector",
+			config: func() *v1beta1.FRRConfiguration {
+				res := withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1"})
+				res.Spec.NodeSelector = metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar baz"}}
+				return res
+			}(),
+			err: true,
+		},
+		{
+			name:        "Conflicting router asn on a selected node",
+			config:      withRouter("test", 65001, nil),
+			objects:     []client.Object{node, withRouter("other", 65002, map[string]string{"rack": "a"})},
+			err:         true,
+			errContains: "default/other",
+		},
+		{
+			name:   "Conflicting neighbor, named among multiple configurations",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1"}),
+			objects: []client.Object{node, withRouter("compatible", 65001, nil), func() *v1beta1.FRRConfiguration {
+				res := withNeighbor(v1beta1.Neighbor{ASN: 65003, Address: "192.0.2.1"})
+				res.Name = "conflicting"
+				return res
+			}()},
+			err:         true,
+			errContains: "default/conflicting",
+		},
+		{
+			name:    "Conflicting router asn on a node not selected by the new configuration",
+			config:  withRouter("test", 65001, map[string]string{"rack": "b"}),
+			objects: []client.Object{node, withRouter("other", 65002, nil)},
+		},
+		{
+			name:    "Conflicting router asn on a node not selected by the existing configuration",
+			config:  withRouter("test", 65001, nil),
+			objects: []client.Object{node, withRouter("other", 65002, map[string]string{"rack": "b"})},
+		},
+		{
+			name:    "Updating the asn of an existing configuration",
+			config:  withRouter("test", 65001, nil),
+			objects: []client.Object{node, withRouter("test", 65002, nil)},
+		},
+		{
+			name:    "Existing configurations already conflicting",
+			config:  withRouter("test", 65001, nil),
+			objects: []client.Object{node, withRouter("other1", 65001, nil), withRouter("other2", 65002, nil)},
+		},
+		{
+			name:        "Conflicting with the existing configurations already conflicting",
+			config:      withRouter("test", 65003, nil),
+			objects:     []client.Object{node, withRouter("other1", 65001, nil), withRouter("other2", 65002, nil)},
+			err:         true,
+			errContains: "default/other1",
+		},
+		{
+			name: "Advertising a prefix declared by another configuration",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+				ToAdvertise: v1beta1.Advertise{Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"10.0.0.0/24"}}}}),
+			objects: []client.Object{node, &v1beta1.FRRConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+				Spec: v1beta1.FRRConfigurationSpec{
+					BGP: v1beta1.BGPConfig{
+						Routers: []v1beta1.Router{{ASN: 65001, Prefixes: []string{"10.0.0.0/24"}}},
+					},
+				},
+			}},
+		},
+		{
+			name: "Advertising a prefix declared by another configuration in a different vrf",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+				ToAdvertise: v1beta1.Advertise{Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"10.0.0.0/24"}}}}),
+			objects: []client.Object{node, &v1beta1.FRRConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+				Spec: v1beta1.FRRConfigurationSpec{
+					BGP: v1beta1.BGPConfig{
+						Routers: []v1beta1.Router{{ASN: 65001, VRF: "red", Prefixes: []string{"10.0.0.0/24"}}},
+					},
+				},
+			}},
+			err: true,
+		},
+		{
+			name:    "Neighbor address from a node label",
+			config:  withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "${node.labels['tor-address']}"}),
+			objects: []client.Object{torNode},
+		},
+		{
+			name:        "Neighbor address from a missing node label",
+			config:      withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "${node.labels['tor-address']}"}),
+			objects:     []client.Object{node},
+			err:         true,
+			errContains: "node1",
+		},
+		{
+			name:    "Neighbor address from a node label not being an address",
+			config:  withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "${node.labels['rack']}"}),
+			objects: []client.Object{torNode},
+			err:     true,
+		},
+		{
+			name:   "Malformed node template",
+			config: withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "${node.label['tor-address']}"}),
+			err:    true,
+		},
+		{
+			name:        "Conflicting router asn resolved from a node label",
+			config:      withASNTemplate("test", "${node.labels['asn']}"),
+			objects:     []client.Object{torNode, withRouter("other", 65001, nil)},
+			err:         true,
+			errContains: "default/other",
+		},
+		{
+			name: "Advertising the pod CIDRs",
+			config: func() *v1beta1.FRRConfiguration {
+				cfg := withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+					ToAdvertise: v1beta1.Advertise{Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"10.244.1.0/24"}}}})
+				cfg.Spec.BGP.Routers[0].AdvertisePodCIDRs = true
+				return cfg
+			}(),
+			objects: []client.Object{podCIDRsNode},
+		},
+		{
+			name: "Advertising the pod CIDRs, allowing a prefix not belonging to the node",
+			config: func() *v1beta1.FRRConfiguration {
+				cfg := withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1",
+					ToAdvertise: v1beta1.Advertise{Allowed: v1beta1.AllowedPrefixes{Prefixes: []string{"10.244.2.0/24"}}}})
+				cfg.Spec.BGP.Routers[0].AdvertisePodCIDRs = true
+				return cfg
+			}(),
+			objects: []client.Object{podCIDRsNode},
+			err:     true,
+		},
+		{
+			name: "Advertising the pod CIDRs of a node without pod CIDRs",
+			config: func() *v1beta1.FRRConfiguration {
+				cfg := withNeighbor(v1beta1.Neighbor{ASN: 65002, Address: "192.0.2.1"})
+				cfg.Spec.BGP.Routers[0].AdvertisePodCIDRs = true
+				return cfg
+			}(),
+			objects: []client.Object{node},
+		},
+		{
+			name:    "Router asn resolved from a node label",
+			config:  withASNTemplate("test", "${node.labels['asn']}"),
+			objects: []client.Object{torNode, withRouter("other", 65010, nil)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(test.objects...).Build()
+			validator := &ConfigValidator{
+				Client:    cli,
+				Logger:    log.NewNopLogger(),
+				Namespace: "frr-k8s-system",
+			}
+			err := validator.Validate(test.config)
+			if test.err && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if test.err && !strings.Contains(err.Error(), test.errContains) {
+				t.Fatalf("expected error to contain %q, got %v", test.errContains, err)
+			}
+			if !test.err && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUsesNodeAddresses(t *testing.T) {
+	withRouter := func(r v1beta1.Router) []v1beta1.FRRConfiguration {
+		return []v1beta1.FRRConfiguration{
+			{Spec: v1beta1.FRRConfigurationSpec{BGP: v1beta1.BGPConfig{Routers: []v1beta1.Router{r}}}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		cfgs     []v1beta1.FRRConfiguration
+		expected bool
+	}{
+		{
+			name:     "No configurations",
+			cfgs:     nil,
+			expected: false,
+		},
+		{
+			name: "Other node templates",
+			cfgs: withRouter(v1beta1.Router{ASN: 65001, ID: "${node.annotations['router-id']}",
+				Neighbors: []v1beta1.Neighbor{{Address: "${node.labels['tor-address']}"}}}),
+			expected: false,
+		},
+		{
+			name: "Source address from the interface",
+			cfgs: withRouter(v1beta1.Router{ASN: 65001,
+				Neighbors: []v1beta1.Neighbor{{Address: "192.0.2.1", SourceAddressFrom: &v1beta1.SourceAddressSelector{Interface: "eth0"}}}}),
+			expected: false,
+		},
+		{
+			name: "Source address from the node internal ip",
+			cfgs: withRouter(v1beta1.Router{ASN: 65001,
+				Neighbors: []v1beta1.Neighbor{{Address: "192.0.2.1", SourceAddressFrom: &v1beta1.SourceAddressSelector{NodeInternalIP: true}}}}),
+			expected: true,
+		},
+		{
+			name:     "Router id from the node internal ip",
+			cfgs:     withRouter(v1beta1.Router{ASN: 65001, ID: "${ node.internalIPv4 }"}),
+			expected: true,
+		},
+		{
+			name: "Neighbor address from the node internal ip",
+			cfgs: withRouter(v1beta1.Router{ASN: 65001,
+				Neighbors: []v1beta1.Neighbor{{Address: "${node.internalIPv6}"}}}),
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if res := usesNodeAddresses(test.cfgs); res != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, res)
+			}
+		})
+	}
+}
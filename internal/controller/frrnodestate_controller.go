@@ -0,0 +1,158 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	frrk8sv1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/frr"
+)
+
+// ConversionStatus represents the result of the last translation of the
+// FRRConfigurations selected by the node.
+type ConversionStatus struct {
+	SelectedConfigurations []string
+	LastConversionResult   string
+	FailedConfigurations   []frrk8sv1beta1.FailedConfiguration
+}
+
+type ConversionStatusFetcher interface {
+	GetConversionStatus() ConversionStatus
+}
+
+// FRRNodeStateReconciler reconciles the FRRNodeState object related to
+// the node the daemon is running on.
+type FRRNodeStateReconciler struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	FRRStatus        frr.StatusFetcher
+	ConversionStatus ConversionStatusFetcher
+	Logger           log.Logger
+	NodeName         string
+	Update           chan event.GenericEvent
+}
+
+// +kubebuilder:rbac:groups=frrk8s.metallb.io,resources=frrnodestates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=frrk8s.metallb.io,resources=frrnodestates/status,verbs=get;update;patch
+
+func (r *FRRNodeStateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	level.Info(r.Logger).Log("controller", "FRRNodeStateReconciler", "start reconcile", req.NamespacedName.String())
+	defer level.Info(r.Logger).Log("controller", "FRRNodeStateReconciler", "end reconcile", req.NamespacedName.String())
+
+	state := &frrk8sv1beta1.FRRNodeState{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: r.NodeName}, state)
+	if k8serrors.IsNotFound(err) {
+		state, err = r.createNodeState(ctx)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	frrStatus := r.FRRStatus.GetStatus()
+	conversionStatus := r.ConversionStatus.GetConversionStatus()
+	desiredStatus := frrk8sv1beta1.FRRNodeStateStatus{
+		RunningConfig:          frrStatus.RunningConfig,
+		LastReloadResult:       frrStatus.LastReloadResult,
+		SelectedConfigurations: conversionStatus.SelectedConfigurations,
+		LastConversionResult:   conversionStatus.LastConversionResult,
+		FailedConfigurations:   conversionStatus.FailedConfigurations,
+	}
+
+	if reflect.DeepEqual(state.Status, desiredStatus) {
+		return ctrl.Result{}, nil
+	}
+
+	state.Status = desiredStatus
+	err = r.Client.Status().Update(ctx, state)
+	if err != nil {
+		level.Error(r.Logger).Log("controller", "FRRNodeStateReconciler", "failed to update the node state", req.NamespacedName.String(), "error", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// createNodeState creates the FRRNodeState for the current node, owned by the node
+// so that it gets garbage collected when the node is deleted.
+func (r *FRRNodeStateReconciler) createNodeState(ctx context.Context) (*frrk8sv1beta1.FRRNodeState, error) {
+	node := &corev1.Node{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: r.NodeName}, node)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &frrk8sv1beta1.FRRNodeState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: r.NodeName,
+		},
+	}
+	err = controllerutil.SetOwnerReference(node, state, r.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Client.Create(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *FRRNodeStateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	p := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetName() == r.NodeName
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&frrk8sv1beta1.FRRNodeState{}).
+		Watches(&source.Channel{Source: r.Update}, &handler.EnqueueRequestForObject{}).
+		WithEventFilter(p).
+		Complete(r)
+}
+
+// NewNodeStateNotifier returns the channel to be passed to the FRRNodeStateReconciler, and
+// a function that triggers a reconciliation of the FRRNodeState of the given node.
+// Notifications are coalesced while one is already pending.
+func NewNodeStateNotifier(nodeName string) (chan event.GenericEvent, func()) {
+	updates := make(chan event.GenericEvent, 1)
+	notify := func() {
+		select {
+		case updates <- event.GenericEvent{Object: &frrk8sv1beta1.FRRNodeState{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}}:
+		default:
+		}
+	}
+	return updates, notify
+}
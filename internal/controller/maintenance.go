@@ -0,0 +1,155 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/frr"
+)
+
+// gracefulShutdownCommunity is the well known GRACEFUL_SHUTDOWN community (RFC 8326).
+const gracefulShutdownCommunity = "65535:0"
+
+// maintenanceStatus is the maintenance status of the node the configuration
+// is translated for.
+type maintenanceStatus struct {
+	active bool
+	// elapsed is the time passed since the node entered maintenance.
+	elapsed time.Duration
+}
+
+// inMaintenance tells if the given node is in maintenance, either because
+// it is cordoned or because it has the maintenance annotation.
+func inMaintenance(node *corev1.Node) bool {
+	if node == nil {
+		return false
+	}
+	if node.Spec.Unschedulable {
+		return true
+	}
+	_, ok := node.Annotations[v1beta1.MaintenanceAnnotation]
+	return ok
+}
+
+// maintenanceFor returns the maintenance settings specified by the given configurations,
+// or nil if none specifies them.
+func maintenanceFor(cfgs []v1beta1.FRRConfiguration) (*v1beta1.Maintenance, error) {
+	var res *v1beta1.Maintenance
+	var resSources []string
+	for _, cfg := range cfgs {
+		m := cfg.Spec.BGP.Maintenance
+		if m == nil {
+			continue
+		}
+		var source []string
+		if s := sourceOf(cfg); s != "" {
+			source = []string{s}
+		}
+		if m.ASPathPrependCount > 10 {
+			return nil, fmt.Errorf("invalid maintenance as-path prepend count %d, must be lower than 11", m.ASPathPrependCount)
+		}
+		if m.WithdrawAfter != nil && m.WithdrawAfter.Duration < 0 {
+			return nil, fmt.Errorf("invalid maintenance withdrawAfter %s, must not be negative", m.WithdrawAfter.Duration)
+		}
+		if res != nil && !reflect.DeepEqual(res, m) {
+			return nil, withSources(fmt.Errorf("multiple maintenance settings specified"), resSources, source)
+		}
+		res = m
+		resSources = mergeSources(resSources, source)
+	}
+	return res, nil
+}
+
+// applyMaintenance changes the advertisements of the given routers according to
+// the maintenance settings. Once the withdraw delay is expired, no prefix is
+// advertised anymore.
+func applyMaintenance(routers []*frr.RouterConfig, m *v1beta1.Maintenance, status maintenanceStatus) {
+	if !status.active {
+		return
+	}
+	if m == nil {
+		m = &v1beta1.Maintenance{}
+	}
+	withdraw := m.WithdrawAfter != nil && status.elapsed >= m.WithdrawAfter.Duration
+	for _, r := range routers {
+		if !withdraw {
+			for i := range r.RedistributeV4 {
+				maintenanceRedistribution(&r.RedistributeV4[i], r.MyASN, m)
+			}
+			for i := range r.RedistributeV6 {
+				maintenanceRedistribution(&r.RedistributeV6[i], r.MyASN, m)
+			}
+		}
+		for _, n := range r.Neighbors {
+			if withdraw {
+				n.Outgoing = frr.AllowedOut{
+					PrefixesV4: []frr.OutgoingFilter{},
+					PrefixesV6: []frr.OutgoingFilter{},
+				}
+				continue
+			}
+			for i := range n.Outgoing.PrefixesV4 {
+				maintenanceFilter(&n.Outgoing.PrefixesV4[i], r.MyASN, m)
+			}
+			for i := range n.Outgoing.PrefixesV6 {
+				maintenanceFilter(&n.Outgoing.PrefixesV6[i], r.MyASN, m)
+			}
+		}
+	}
+}
+
+func maintenanceFilter(f *frr.OutgoingFilter, asn uint32, m *v1beta1.Maintenance) {
+	if !contains(f.Communities, gracefulShutdownCommunity) {
+		communities := append([]string{}, f.Communities...)
+		f.Communities = append(communities, gracefulShutdownCommunity)
+	}
+	if m.ASPathPrependCount > 0 {
+		f.ASPathPrepend = &frr.ASPathPrepend{ASN: asn, Count: m.ASPathPrependCount}
+	}
+	if m.LocalPref > 0 {
+		f.LocalPref = m.LocalPref
+	}
+}
+
+// maintenanceRedistribution tags the redistributed routes, which are not
+// covered by the neighbors' advertisements.
+func maintenanceRedistribution(d *frr.Redistribution, asn uint32, m *v1beta1.Maintenance) {
+	if !contains(d.Communities, gracefulShutdownCommunity) {
+		communities := append([]string{}, d.Communities...)
+		d.Communities = append(communities, gracefulShutdownCommunity)
+	}
+	if m.ASPathPrependCount > 0 {
+		d.ASPathPrepend = &frr.ASPathPrepend{ASN: asn, Count: m.ASPathPrependCount}
+	}
+	if m.LocalPref > 0 {
+		d.LocalPref = m.LocalPref
+	}
+}
+
+// withdrawDelay returns the time left before the prefixes are withdrawn
+// from the neighbors, if they are going to be.
+func withdrawDelay(m *v1beta1.Maintenance, status maintenanceStatus) (time.Duration, bool) {
+	if !status.active || m == nil || m.WithdrawAfter == nil {
+		return 0, false
+	}
+	left := m.WithdrawAfter.Duration - status.elapsed
+	if left <= 0 {
+		return 0, false
+	}
+	return left, true
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
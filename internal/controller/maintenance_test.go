@@ -0,0 +1,276 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/frr"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+)
+
+func TestInMaintenance(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     *corev1.Node
+		expected bool
+	}{
+		{
+			name:     "no node",
+			expected: false,
+		},
+		{
+			name:     "schedulable node",
+			node:     &corev1.Node{},
+			expected: false,
+		},
+		{
+			name:     "cordoned node",
+			node:     &corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true}},
+			expected: true,
+		},
+		{
+			name: "annotated node",
+			node: &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{v1beta1.MaintenanceAnnotation: ""},
+			}},
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if res := inMaintenance(test.node); res != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestMaintenance(t *testing.T) {
+	configWith := func(name string, m *v1beta1.Maintenance) v1beta1.FRRConfiguration {
+		return v1beta1.FRRConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1beta1.FRRConfigurationSpec{
+				BGP: v1beta1.BGPConfig{
+					Routers: []v1beta1.Router{
+						{
+							ASN:      65001,
+							Prefixes: []string{"192.168.2.0/24"},
+							Neighbors: []v1beta1.Neighbor{
+								{
+									ASN:     65002,
+									Address: "192.168.1.2",
+									ToAdvertise: v1beta1.Advertise{
+										Allowed: v1beta1.AllowedPrefixes{Mode: v1beta1.AllowAll},
+										PrefixesWithCommunity: []v1beta1.CommunityPrefixes{
+											{Prefixes: []string{"192.168.2.0/24"}, Community: "10:100"},
+										},
+									},
+								},
+							},
+						},
+					},
+					Maintenance: m,
+				},
+			},
+		}
+	}
+	source := []string{"default/config"}
+
+	tests := []struct {
+		name          string
+		cfgs          []v1beta1.FRRConfiguration
+		status        maintenanceStatus
+		expected      frr.AllowedOut
+		expectedDelay time.Duration
+		err           bool
+	}{
+		{
+			name:   "not in maintenance",
+			cfgs:   []v1beta1.FRRConfiguration{configWith("config", &v1beta1.Maintenance{ASPathPrependCount: 3})},
+			status: maintenanceStatus{},
+			expected: frr.AllowedOut{
+				PrefixesV4: []frr.OutgoingFilter{
+					{IPFamily: ipfamily.IPv4, Prefix: "192.168.2.0/24", Communities: []string{"10:100"}, Sources: source},
+				},
+				PrefixesV6: []frr.OutgoingFilter{},
+			},
+		},
+		{
+			name:   "in maintenance, no settings",
+			cfgs:   []v1beta1.FRRConfiguration{configWith("config", nil)},
+			status: maintenanceStatus{active: true},
+			expected: frr.AllowedOut{
+				PrefixesV4: []frr.OutgoingFilter{
+					{IPFamily: ipfamily.IPv4, Prefix: "192.168.2.0/24", Communities: []string{"10:100", "65535:0"}, Sources: source},
+				},
+				PrefixesV6: []frr.OutgoingFilter{},
+			},
+		},
+		{
+			name: "in maintenance, prepend and local pref before withdrawing",
+			cfgs: []v1beta1.FRRConfiguration{configWith("config", &v1beta1.Maintenance{
+				ASPathPrependCount: 3,
+				LocalPref:          50,
+				WithdrawAfter:      &metav1.Duration{Duration: time.Minute},
+			})},
+			status: maintenanceStatus{active: true, elapsed: 20 * time.Second},
+			expected: frr.AllowedOut{
+				PrefixesV4: []frr.OutgoingFilter{
+					{
+						IPFamily:      ipfamily.IPv4,
+						Prefix:        "192.168.2.0/24",
+						Communities:   []string{"10:100", "65535:0"},
+						ASPathPrepend: &frr.ASPathPrepend{ASN: 65001, Count: 3},
+						LocalPref:     50,
+						Sources:       source,
+					},
+				},
+				PrefixesV6: []frr.OutgoingFilter{},
+			},
+			expectedDelay: 40 * time.Second,
+		},
+		{
+			name: "in maintenance, withdrawn",
+			cfgs: []v1beta1.FRRConfiguration{configWith("config", &v1beta1.Maintenance{
+				WithdrawAfter: &metav1.Duration{Duration: time.Minute},
+			})},
+			status: maintenanceStatus{active: true, elapsed: 2 * time.Minute},
+			expected: frr.AllowedOut{
+				PrefixesV4: []frr.OutgoingFilter{},
+				PrefixesV6: []frr.OutgoingFilter{},
+			},
+		},
+		{
+			name: "conflicting settings",
+			cfgs: []v1beta1.FRRConfiguration{
+				configWith("config", &v1beta1.Maintenance{ASPathPrependCount: 3}),
+				configWith("config1", &v1beta1.Maintenance{ASPathPrependCount: 2}),
+			},
+			status: maintenanceStatus{active: true},
+			err:    true,
+		},
+		{
+			name:   "negative withdraw delay",
+			cfgs:   []v1beta1.FRRConfiguration{configWith("config", &v1beta1.Maintenance{WithdrawAfter: &metav1.Duration{Duration: -time.Minute}})},
+			status: maintenanceStatus{active: true},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := apiToFRR(test.cfgs, clusterResources{maintenance: test.status})
+			if test.err && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if test.err {
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(test.expected, res.Routers[0].Neighbors[0].Outgoing) {
+				t.Fatalf("config different from expected: %s", cmp.Diff(test.expected, res.Routers[0].Neighbors[0].Outgoing))
+			}
+
+			m, err := maintenanceFor(test.cfgs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			delay, _ := withdrawDelay(m, test.status)
+			if delay != test.expectedDelay {
+				t.Fatalf("expected withdraw delay %s, got %s", test.expectedDelay, delay)
+			}
+		})
+	}
+}
+
+func TestMaintenanceRedistribution(t *testing.T) {
+	routers := func() []*frr.RouterConfig {
+		return []*frr.RouterConfig{
+			{
+				MyASN: 65001,
+				RedistributeV4: []frr.Redistribution{
+					{IPFamily: ipfamily.IPv4, Source: "connected", Communities: []string{"10:100"}},
+				},
+				RedistributeV6: []frr.Redistribution{
+					{IPFamily: ipfamily.IPv6, Source: "kernel", LocalPref: 200},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		m          *v1beta1.Maintenance
+		status     maintenanceStatus
+		expectedV4 []frr.Redistribution
+		expectedV6 []frr.Redistribution
+	}{
+		{
+			name:   "not in maintenance",
+			m:      &v1beta1.Maintenance{ASPathPrependCount: 3},
+			status: maintenanceStatus{},
+			expectedV4: []frr.Redistribution{
+				{IPFamily: ipfamily.IPv4, Source: "connected", Communities: []string{"10:100"}},
+			},
+			expectedV6: []frr.Redistribution{
+				{IPFamily: ipfamily.IPv6, Source: "kernel", LocalPref: 200},
+			},
+		},
+		{
+			name:   "in maintenance, no settings",
+			m:      nil,
+			status: maintenanceStatus{active: true},
+			expectedV4: []frr.Redistribution{
+				{IPFamily: ipfamily.IPv4, Source: "connected", Communities: []string{"10:100", "65535:0"}},
+			},
+			expectedV6: []frr.Redistribution{
+				{IPFamily: ipfamily.IPv6, Source: "kernel", Communities: []string{"65535:0"}, LocalPref: 200},
+			},
+		},
+		{
+			name:   "in maintenance, prepend and local pref",
+			m:      &v1beta1.Maintenance{ASPathPrependCount: 3, LocalPref: 50},
+			status: maintenanceStatus{active: true},
+			expectedV4: []frr.Redistribution{
+				{
+					IPFamily:      ipfamily.IPv4,
+					Source:        "connected",
+					Communities:   []string{"10:100", "65535:0"},
+					LocalPref:     50,
+					ASPathPrepend: &frr.ASPathPrepend{ASN: 65001, Count: 3},
+				},
+			},
+			expectedV6: []frr.Redistribution{
+				{
+					IPFamily:      ipfamily.IPv6,
+					Source:        "kernel",
+					Communities:   []string{"65535:0"},
+					LocalPref:     50,
+					ASPathPrepend: &frr.ASPathPrepend{ASN: 65001, Count: 3},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := routers()
+			applyMaintenance(res, test.m, test.status)
+			if !cmp.Equal(test.expectedV4, res[0].RedistributeV4) {
+				t.Fatalf("ipv4 redistributions different from expected: %s", cmp.Diff(test.expectedV4, res[0].RedistributeV4))
+			}
+			if !cmp.Equal(test.expectedV6, res[0].RedistributeV6) {
+				t.Fatalf("ipv6 redistributions different from expected: %s", cmp.Diff(test.expectedV6, res[0].RedistributeV6))
+			}
+		})
+	}
+}
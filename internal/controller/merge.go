@@ -4,6 +4,8 @@ package controller
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/metallb/frrk8s/internal/frr"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -13,13 +15,32 @@ import (
 func mergeRouterConfigs(r, toMerge *frr.RouterConfig) (*frr.RouterConfig, error) {
 	err := routersAreCompatible(r, toMerge)
 	if err != nil {
-		return nil, err
+		return nil, withSources(err, r.Sources, toMerge.Sources)
 	}
 
 	if r.RouterID == "" {
 		r.RouterID = toMerge.RouterID
 	}
 
+	if r.GracefulRestart == nil {
+		r.GracefulRestart = toMerge.GracefulRestart
+	}
+
+	if r.EVPN == nil {
+		r.EVPN = toMerge.EVPN
+	}
+
+	r.Imports = mergeImports(r.Imports, toMerge.Imports)
+	r.RedistributeV4, err = mergeRedistributions(r.VRF, r.RedistributeV4, toMerge.RedistributeV4)
+	if err != nil {
+		return nil, err
+	}
+	r.RedistributeV6, err = mergeRedistributions(r.VRF, r.RedistributeV6, toMerge.RedistributeV6)
+	if err != nil {
+		return nil, err
+	}
+	r.VPN = mergeVPN(r.VPN, toMerge.VPN)
+
 	v4Prefixes := sets.New(append(r.IPV4Prefixes, toMerge.IPV4Prefixes...)...)
 	v6Prefixes := sets.New(append(r.IPV6Prefixes, toMerge.IPV6Prefixes...)...)
 
@@ -31,10 +52,129 @@ func mergeRouterConfigs(r, toMerge *frr.RouterConfig) (*frr.RouterConfig, error)
 	r.IPV4Prefixes = sets.List(v4Prefixes)
 	r.IPV6Prefixes = sets.List(v6Prefixes)
 	r.Neighbors = mergedNeighbors
+	r.Sources = mergeSources(r.Sources, toMerge.Sources)
+	for p, sources := range toMerge.PrefixSources {
+		if r.PrefixSources == nil {
+			r.PrefixSources = map[string][]string{}
+		}
+		r.PrefixSources[p] = mergeSources(r.PrefixSources[p], sources)
+	}
+
+	return r, nil
+}
 
+// Merges two OSPF router configs of the same VRF.
+func mergeOSPFRouters(r, toMerge *frr.OSPFRouterConfig) (*frr.OSPFRouterConfig, error) {
+	bothRouterIDsNonEmpty := r.RouterID != "" && toMerge.RouterID != ""
+	if bothRouterIDsNonEmpty && r.RouterID != toMerge.RouterID {
+		err := fmt.Errorf("different ospf router ids (%s != %s) specified for same vrf: %s", r.RouterID, toMerge.RouterID, r.VRF)
+		return nil, withSources(err, r.Sources, toMerge.Sources)
+	}
+	if r.RouterID == "" {
+		r.RouterID = toMerge.RouterID
+	}
+
+	interfaces, err := mergeOSPFInterfaces(r.Interfaces, toMerge.Interfaces)
+	if err != nil {
+		return nil, err
+	}
+	r.Interfaces = interfaces
+	r.Prefixes = sets.List(sets.New(append(r.Prefixes, toMerge.Prefixes...)...))
+	r.Sources = mergeSources(r.Sources, toMerge.Sources)
 	return r, nil
 }
 
+// Merges the interfaces of the same OSPF router, which must be equal when
+// specified multiple times.
+func mergeOSPFInterfaces(curr, toMerge []frr.OSPFInterface) ([]frr.OSPFInterface, error) {
+	res := append([]frr.OSPFInterface{}, curr...)
+	indexes := map[string]int{}
+	for i, iface := range res {
+		indexes[iface.Name] = i
+	}
+	for _, iface := range toMerge {
+		i, ok := indexes[iface.Name]
+		if !ok {
+			indexes[iface.Name] = len(res)
+			res = append(res, iface)
+			continue
+		}
+		c, m := res[i], iface
+		c.Sources, m.Sources = nil, nil
+		if !reflect.DeepEqual(c, m) {
+			err := fmt.Errorf("multiple configurations specified for ospf interface %s", iface.Name)
+			return nil, withSources(err, res[i].Sources, iface.Sources)
+		}
+		res[i].Sources = mergeSources(res[i].Sources, iface.Sources)
+	}
+	sortOSPFInterfaces(res)
+	return res, nil
+}
+
+// Merges the redistributions of the same router and family. The routes of
+// the same source are filtered by all the ranges, unless one of the
+// redistributions is not filtered, and must be tagged in the same way.
+func mergeRedistributions(vrf string, curr, toMerge []frr.Redistribution) ([]frr.Redistribution, error) {
+	if len(curr) == 0 && len(toMerge) == 0 {
+		return curr, nil
+	}
+	res := append([]frr.Redistribution{}, curr...)
+	indexes := map[string]int{}
+	for i, d := range res {
+		indexes[d.Name()] = i
+	}
+	for _, d := range toMerge {
+		i, ok := indexes[d.Name()]
+		if !ok {
+			indexes[d.Name()] = len(res)
+			res = append(res, d)
+			continue
+		}
+		c := &res[i]
+		if !reflect.DeepEqual(c.Communities, d.Communities) ||
+			!reflect.DeepEqual(c.LargeCommunities, d.LargeCommunities) ||
+			c.LocalPref != d.LocalPref {
+			err := fmt.Errorf("different tagging specified for the redistributed %s %s routes at vrf %s", d.IPFamily, d.Name(), vrf)
+			return nil, withSources(err, c.Sources, d.Sources)
+		}
+		if len(c.Prefixes) == 0 || len(d.Prefixes) == 0 {
+			c.Prefixes = nil
+		} else {
+			c.Prefixes = mergeIncomingRanges(c.Prefixes, d.Prefixes)
+		}
+		c.Sources = mergeSources(c.Sources, d.Sources)
+	}
+	sortRedistributions(res)
+	return res, nil
+}
+
+// Merges the static routes of the same VRF. A prefix can be routed only once,
+// so the routes to the same prefix must be equal.
+func mergeStaticRoutes(vrf string, curr, toMerge []frr.StaticRoute) ([]frr.StaticRoute, error) {
+	res := append([]frr.StaticRoute{}, curr...)
+	indexes := map[string]int{}
+	for i, r := range res {
+		indexes[r.Prefix] = i
+	}
+	for _, r := range toMerge {
+		i, ok := indexes[r.Prefix]
+		if !ok {
+			indexes[r.Prefix] = len(res)
+			res = append(res, r)
+			continue
+		}
+		c, m := res[i], r
+		c.Sources, m.Sources = nil, nil
+		if !reflect.DeepEqual(c, m) {
+			err := fmt.Errorf("multiple static routes specified for prefix %s at vrf %s", r.Prefix, vrf)
+			return nil, withSources(err, res[i].Sources, r.Sources)
+		}
+		res[i].Sources = mergeSources(res[i].Sources, r.Sources)
+	}
+	sortStaticRoutes(res)
+	return res, nil
+}
+
 // Merges two neighbors slices corresponding to the same router.
 func mergeNeighbors(curr, toMerge []*frr.NeighborConfig) ([]*frr.NeighborConfig, error) {
 	all := curr
@@ -46,30 +186,45 @@ func mergeNeighbors(curr, toMerge []*frr.NeighborConfig) ([]*frr.NeighborConfig,
 	mergedNeighbors := map[string]*frr.NeighborConfig{}
 
 	for _, n := range all {
-		curr, found := mergedNeighbors[n.Addr]
+		key := neighborKey(n)
+		curr, found := mergedNeighbors[key]
 		if !found {
-			mergedNeighbors[n.Addr] = n
+			mergedNeighbors[key] = n
 			continue
 		}
 
 		err := neighborsAreCompatible(curr, n)
 		if err != nil {
-			return nil, err
+			return nil, withSources(err, curr.Sources, n.Sources)
 		}
 
 		curr.Outgoing, err = mergeAllowedOut(curr.Outgoing, n.Outgoing)
 		if err != nil {
-			return nil, fmt.Errorf("could not merge outgoing for neighbor %s vrf %s, err: %w", n.Addr, n.VRFName, err)
+			return nil, fmt.Errorf("could not merge outgoing for neighbor %s vrf %s, err: %w", n.Peer(), n.VRFName, err)
 		}
 
 		curr.Incoming = mergeAllowedIn(curr.Incoming, n.Incoming)
+		curr.Sources = mergeSources(curr.Sources, n.Sources)
 
-		mergedNeighbors[n.Addr] = curr
+		mergedNeighbors[key] = curr
 	}
 
 	return sortMapPtr(mergedNeighbors), nil
 }
 
+// Returns the key identifying a neighbor within a router. Unnumbered and dynamic
+// neighbors are keyed by their interface and listen range, in namespaces separated
+// from the addresses.
+func neighborKey(n *frr.NeighborConfig) string {
+	if n.Iface != "" {
+		return "interface:" + n.Iface
+	}
+	if n.ListenRange != "" {
+		return "range:" + n.ListenRange
+	}
+	return n.Addr
+}
+
 // Merges the allowed out prefixes, assuming they are for the same neighbor.
 func mergeAllowedOut(r, toMerge frr.AllowedOut) (frr.AllowedOut, error) {
 	res := frr.AllowedOut{
@@ -88,6 +243,9 @@ func mergeAllowedOut(r, toMerge frr.AllowedOut) (frr.AllowedOut, error) {
 		return frr.AllowedOut{}, err
 	}
 
+	res.RedistributedV4 = mergeIncomingRanges(r.RedistributedV4, toMerge.RedistributedV4)
+	res.RedistributedV6 = mergeIncomingRanges(r.RedistributedV6, toMerge.RedistributedV6)
+
 	return res, nil
 }
 
@@ -108,13 +266,32 @@ func mergeOutgoingFilters(curr, toMerge []frr.OutgoingFilter) ([]frr.OutgoingFil
 		}
 
 		if curr.LocalPref != 0 && f.LocalPref != 0 && curr.LocalPref != f.LocalPref {
-			return nil, fmt.Errorf("multiple local prefs (%d != %d) specified for prefix %s", curr.LocalPref, f.LocalPref, curr.Prefix)
+			err := fmt.Errorf("multiple local prefs (%d != %d) specified for prefix %s", curr.LocalPref, f.LocalPref, curr.Prefix)
+			return nil, withSources(err, curr.Sources, f.Sources)
 		}
 
 		if f.LocalPref != 0 {
 			curr.LocalPref = f.LocalPref
 		}
 
+		if curr.MED != nil && f.MED != nil && *curr.MED != *f.MED {
+			err := fmt.Errorf("multiple meds (%d != %d) specified for prefix %s", *curr.MED, *f.MED, curr.Prefix)
+			return nil, withSources(err, curr.Sources, f.Sources)
+		}
+
+		if f.MED != nil {
+			curr.MED = f.MED
+		}
+
+		if curr.ASPathPrepend != nil && f.ASPathPrepend != nil && *curr.ASPathPrepend != *f.ASPathPrepend {
+			err := fmt.Errorf("multiple as-path prepends (%s != %s) specified for prefix %s", curr.ASPathPrepend.Value(), f.ASPathPrepend.Value(), curr.Prefix)
+			return nil, withSources(err, curr.Sources, f.Sources)
+		}
+
+		if f.ASPathPrepend != nil {
+			curr.ASPathPrepend = f.ASPathPrepend
+		}
+
 		communities := sets.New(append(curr.Communities, f.Communities...)...)
 		curr.Communities = sets.List(communities)
 		if communities.Len() == 0 {
@@ -127,6 +304,14 @@ func mergeOutgoingFilters(curr, toMerge []frr.OutgoingFilter) ([]frr.OutgoingFil
 			curr.LargeCommunities = nil
 		}
 
+		extendedCommunities := sets.New(append(curr.ExtendedCommunities, f.ExtendedCommunities...)...)
+		curr.ExtendedCommunities = sets.List(extendedCommunities)
+		if extendedCommunities.Len() == 0 {
+			curr.ExtendedCommunities = nil
+		}
+
+		curr.Sources = mergeSources(curr.Sources, f.Sources)
+
 		mergedOut[curr.Prefix] = curr
 	}
 
@@ -138,6 +323,7 @@ func mergeAllowedIn(r, toMerge frr.AllowedIn) frr.AllowedIn {
 	res := frr.AllowedIn{
 		PrefixesV4: make([]frr.IncomingFilter, 0),
 		PrefixesV6: make([]frr.IncomingFilter, 0),
+		Policies:   mergeIncomingPolicies(r.Policies, toMerge.Policies),
 	}
 	if r.All || toMerge.All {
 		res.All = true
@@ -146,6 +332,8 @@ func mergeAllowedIn(r, toMerge frr.AllowedIn) frr.AllowedIn {
 
 	res.PrefixesV4 = mergeIncomingFilters(r.PrefixesV4, toMerge.PrefixesV4)
 	res.PrefixesV6 = mergeIncomingFilters(r.PrefixesV6, toMerge.PrefixesV6)
+	res.RangesV4 = mergeIncomingRanges(r.RangesV4, toMerge.RangesV4)
+	res.RangesV6 = mergeIncomingRanges(r.RangesV6, toMerge.RangesV6)
 
 	return res
 }
@@ -160,8 +348,9 @@ func mergeIncomingFilters(curr, toMerge []frr.IncomingFilter) []frr.IncomingFilt
 	mergedIn := map[string]*frr.IncomingFilter{}
 	for _, a := range all {
 		f := a
-		_, found := mergedIn[f.Prefix]
+		curr, found := mergedIn[f.Prefix]
 		if found {
+			curr.Sources = mergeSources(curr.Sources, f.Sources)
 			continue
 		}
 		mergedIn[f.Prefix] = &f
@@ -170,6 +359,138 @@ func mergeIncomingFilters(curr, toMerge []frr.IncomingFilter) []frr.IncomingFilt
 	return sortMap(mergedIn)
 }
 
+// mergeIncomingRanges appends the ranges to merge after the current ones,
+// so the ranges coming from the configurations processed first take precedence
+// when they overlap. The ranges already present are not repeated.
+func mergeIncomingRanges(curr, toMerge []frr.IncomingFilter) []frr.IncomingFilter {
+	var res []frr.IncomingFilter
+	indexes := map[string]int{}
+	all := curr
+	all = append(all, toMerge...)
+	for _, f := range all {
+		key := fmt.Sprintf("%s-%s", f.Action(), f.Match())
+		i, found := indexes[key]
+		if found {
+			res[i].Sources = mergeSources(res[i].Sources, f.Sources)
+			continue
+		}
+		indexes[key] = len(res)
+		res = append(res, f)
+	}
+	return res
+}
+
+// mergeImports merges the imports of the same router, sorted by VRF. The routes
+// imported from the same VRF are filtered by all the ranges, unless one of the
+// imports is not filtered.
+func mergeImports(curr, toMerge []frr.VRFImport) []frr.VRFImport {
+	if len(curr) == 0 && len(toMerge) == 0 {
+		return curr
+	}
+	byVRF := map[string]*frr.VRFImport{}
+	for _, i := range append(curr, toMerge...) {
+		i := i
+		c, ok := byVRF[i.VRF]
+		if !ok {
+			byVRF[i.VRF] = &i
+			continue
+		}
+		c.All = c.All || i.All
+		c.PrefixesV4 = mergeIncomingRanges(c.PrefixesV4, i.PrefixesV4)
+		c.PrefixesV6 = mergeIncomingRanges(c.PrefixesV6, i.PrefixesV6)
+		c.Sources = mergeSources(c.Sources, i.Sources)
+	}
+	res := make([]frr.VRFImport, 0, len(byVRF))
+	for _, i := range byVRF {
+		if i.All {
+			i.PrefixesV4 = []frr.IncomingFilter{}
+			i.PrefixesV6 = []frr.IncomingFilter{}
+		}
+		res = append(res, *i)
+	}
+	sortImports(res)
+	return res
+}
+
+// mergeVPN merges the VPN leaking settings of the same router, assuming
+// their route distinguishers are compatible.
+func mergeVPN(curr, toMerge *frr.VPNLeak) *frr.VPNLeak {
+	if curr == nil {
+		return toMerge
+	}
+	if toMerge == nil {
+		return curr
+	}
+	res := &frr.VPNLeak{
+		RouteDistinguisher: curr.RouteDistinguisher,
+		ImportRouteTargets: sets.List(sets.New(append(curr.ImportRouteTargets, toMerge.ImportRouteTargets...)...)),
+		ExportRouteTargets: sets.List(sets.New(append(curr.ExportRouteTargets, toMerge.ExportRouteTargets...)...)),
+	}
+	if res.RouteDistinguisher == "" {
+		res.RouteDistinguisher = toMerge.RouteDistinguisher
+	}
+	return res
+}
+
+// mergeIncomingPolicies appends the policies not already present, preserving
+// their order.
+func mergeIncomingPolicies(curr, toMerge []frr.IncomingPolicy) []frr.IncomingPolicy {
+	var res []frr.IncomingPolicy
+	for _, p := range append(curr, toMerge...) {
+		found := false
+		for i := range res {
+			if samePolicy(res[i], p) {
+				res[i].Sources = mergeSources(res[i].Sources, p.Sources)
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func samePolicy(a, b frr.IncomingPolicy) bool {
+	a.Sources = nil
+	b.Sources = nil
+	return reflect.DeepEqual(a, b)
+}
+
+// Merges the given bfd profile into the profiles map. A profile with the same
+// name can be defined by multiple configurations only if the profiles are equal.
+func mergeBFDProfiles(profiles map[string]*frr.BFDProfile, toMerge *frr.BFDProfile) error {
+	curr, found := profiles[toMerge.Name]
+	if !found {
+		profiles[toMerge.Name] = toMerge
+		return nil
+	}
+
+	if !reflect.DeepEqual(curr, toMerge) {
+		return fmt.Errorf("multiple bfd profiles specified for %s with different values", toMerge.Name)
+	}
+
+	return nil
+}
+
+// Merges the sources of two merged objects.
+func mergeSources(curr, toMerge []string) []string {
+	sources := sets.New(append(curr, toMerge...)...)
+	if sources.Len() == 0 {
+		return nil
+	}
+	return sets.List(sources)
+}
+
+// Adds the sources of the two objects that failed to be merged to the given error.
+func withSources(err error, curr, toMerge []string) error {
+	if len(curr) == 0 && len(toMerge) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w, conflicting configurations: [%s] and [%s]", err, strings.Join(curr, ", "), strings.Join(toMerge, ", "))
+}
+
 // Verifies that two routers are compatible for merging.
 func routersAreCompatible(r, toMerge *frr.RouterConfig) error {
 	if r.VRF != toMerge.VRF {
@@ -186,6 +507,24 @@ func routersAreCompatible(r, toMerge *frr.RouterConfig) error {
 		return fmt.Errorf("different router ids (%s != %s) specified for same vrf: %s", r.RouterID, toMerge.RouterID, r.VRF)
 	}
 
+	bothGracefulRestartsSet := r.GracefulRestart != nil && toMerge.GracefulRestart != nil
+	if bothGracefulRestartsSet && !reflect.DeepEqual(r.GracefulRestart, toMerge.GracefulRestart) {
+		return fmt.Errorf("different graceful restart configurations specified for same vrf: %s", r.VRF)
+	}
+
+	bothEVPNsSet := r.EVPN != nil && toMerge.EVPN != nil
+	if bothEVPNsSet && !reflect.DeepEqual(r.EVPN, toMerge.EVPN) {
+		return fmt.Errorf("different evpn configurations specified for same vrf: %s", r.VRF)
+	}
+
+	if r.VPN != nil && toMerge.VPN != nil {
+		bothRDsNonEmpty := r.VPN.RouteDistinguisher != "" && toMerge.VPN.RouteDistinguisher != ""
+		if bothRDsNonEmpty && r.VPN.RouteDistinguisher != toMerge.VPN.RouteDistinguisher {
+			return fmt.Errorf("different route distinguishers (%s != %s) specified for same vrf: %s",
+				r.VPN.RouteDistinguisher, toMerge.VPN.RouteDistinguisher, r.VRF)
+		}
+	}
+
 	return nil
 }
 
@@ -195,12 +534,20 @@ func neighborsAreCompatible(n1, n2 *frr.NeighborConfig) error {
 		return fmt.Errorf("neighbors with different addresses (%s != %s) are not compatible for merging", n1.Addr, n2.Addr)
 	}
 
+	if n1.Iface != n2.Iface {
+		return fmt.Errorf("neighbors with different interfaces (%s != %s) are not compatible for merging", n1.Iface, n2.Iface)
+	}
+
+	if n1.ListenRange != n2.ListenRange {
+		return fmt.Errorf("neighbors with different listen ranges (%s != %s) are not compatible for merging", n1.ListenRange, n2.ListenRange)
+	}
+
 	if n1.VRFName != n2.VRFName {
 		return fmt.Errorf("neighbors using a different VRF (%s != %s) are not compatible for merging", n1.VRFName, n2.VRFName)
 	}
 
-	neighborKey := fmt.Sprintf("neighbor %s at vrf %s", n1.Addr, n1.VRFName)
-	if n1.ASN != n2.ASN {
+	neighborKey := fmt.Sprintf("neighbor %s at vrf %s", n1.Peer(), n1.VRFName)
+	if n1.ASN != n2.ASN || n1.DynamicASN != n2.DynamicASN {
 		return fmt.Errorf("multiple asns specified for %s", neighborKey)
 	}
 
@@ -224,6 +571,10 @@ func neighborsAreCompatible(n1, n2 *frr.NeighborConfig) error {
 		return fmt.Errorf("conflicting ebgp-multihop specified for %s", neighborKey)
 	}
 
+	if n1.ActivateEVPN != n2.ActivateEVPN {
+		return fmt.Errorf("conflicting evpn activation specified for %s", neighborKey)
+	}
+
 	if n1.HoldTime != n2.HoldTime {
 		return fmt.Errorf("multiple hold times specified for %s", neighborKey)
 	}
@@ -232,5 +583,17 @@ func neighborsAreCompatible(n1, n2 *frr.NeighborConfig) error {
 		return fmt.Errorf("multiple keepalive times specified for %s", neighborKey)
 	}
 
+	if !reflect.DeepEqual(n1.MaxPrefixesV4, n2.MaxPrefixesV4) || !reflect.DeepEqual(n1.MaxPrefixesV6, n2.MaxPrefixesV6) {
+		return fmt.Errorf("multiple max prefixes specified for %s", neighborKey)
+	}
+
+	if n1.GracefulRestart != n2.GracefulRestart {
+		return fmt.Errorf("multiple graceful restart modes specified for %s", neighborKey)
+	}
+
+	if n1.SessionLimit != n2.SessionLimit {
+		return fmt.Errorf("multiple session limits specified for %s", neighborKey)
+	}
+
 	return nil
 }
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+)
+
+// neighborTemplates holds the neighbor templates defined by a set of configurations,
+// and the template each neighbor is bound to.
+type neighborTemplates struct {
+	byName map[string]v1beta1.NeighborTemplate
+	// bound maps each neighbor to the template it inherits from, so that the
+	// same neighbor declared by multiple configurations gets the same values.
+	bound map[string]string
+}
+
+// templatesFor collects the neighbor templates defined by the given configurations,
+// and binds the neighbors referencing them.
+func templatesFor(cfgs []v1beta1.FRRConfiguration) (*neighborTemplates, error) {
+	res := &neighborTemplates{
+		byName: map[string]v1beta1.NeighborTemplate{},
+		bound:  map[string]string{},
+	}
+	templateSources := map[string][]string{}
+	for _, cfg := range cfgs {
+		var source []string
+		if s := sourceOf(cfg); s != "" {
+			source = []string{s}
+		}
+		for _, t := range cfg.Spec.BGP.NeighborTemplates {
+			_, _, err := timersForNeighbor(t.HoldTime, t.KeepaliveTime)
+			if err != nil {
+				return nil, fmt.Errorf("invalid neighbor template %s: %w", t.Name, err)
+			}
+			_, _, err = maxPrefixesForNeighbor(t.MaxPrefixes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid neighbor template %s: %w", t.Name, err)
+			}
+			curr, ok := res.byName[t.Name]
+			if ok && !reflect.DeepEqual(curr, t) {
+				err := fmt.Errorf("multiple neighbor templates specified for %s with different values", t.Name)
+				return nil, withSources(err, templateSources[t.Name], source)
+			}
+			res.byName[t.Name] = t
+			templateSources[t.Name] = mergeSources(templateSources[t.Name], source)
+		}
+	}
+
+	for _, cfg := range cfgs {
+		for _, r := range cfg.Spec.BGP.Routers {
+			for _, n := range r.Neighbors {
+				if n.Template == "" {
+					continue
+				}
+				if _, ok := res.byName[n.Template]; !ok {
+					return nil, fmt.Errorf("neighbor %s at vrf %s references non existing template %s", neighborName(n), r.VRF, n.Template)
+				}
+				key := templateKey(r.VRF, n)
+				curr, ok := res.bound[key]
+				if ok && curr != n.Template {
+					return nil, fmt.Errorf("neighbor %s at vrf %s references multiple templates (%s != %s)", neighborName(n), r.VRF, curr, n.Template)
+				}
+				res.bound[key] = n.Template
+			}
+		}
+	}
+	return res, nil
+}
+
+// resolve returns a copy of the given router where the neighbors bound to a
+// template inherit its values.
+func (t *neighborTemplates) resolve(r v1beta1.Router) v1beta1.Router {
+	res := r
+	res.Neighbors = make([]v1beta1.Neighbor, 0, len(r.Neighbors))
+	for _, n := range r.Neighbors {
+		name, ok := t.bound[templateKey(r.VRF, n)]
+		if !ok {
+			res.Neighbors = append(res.Neighbors, n)
+			continue
+		}
+		res.Neighbors = append(res.Neighbors, withTemplate(n, t.byName[name]))
+	}
+	return res
+}
+
+// withTemplate returns a copy of the given neighbor inheriting the values not set
+// from the template. The template's policies are added to the neighbor's ones.
+func withTemplate(n v1beta1.Neighbor, t v1beta1.NeighborTemplate) v1beta1.Neighbor {
+	res := n
+	res.Template = t.Name
+	if res.PasswordSecret.Name == "" {
+		res.PasswordSecret = t.PasswordSecret
+	}
+	if res.HoldTime.Duration == 0 {
+		res.HoldTime = t.HoldTime
+	}
+	if res.KeepaliveTime.Duration == 0 {
+		res.KeepaliveTime = t.KeepaliveTime
+	}
+	if res.BFDProfile == "" {
+		res.BFDProfile = t.BFDProfile
+	}
+	if res.GracefulRestart == "" {
+		res.GracefulRestart = t.GracefulRestart
+	}
+	if res.MaxPrefixes.IPv4 == nil {
+		res.MaxPrefixes.IPv4 = t.MaxPrefixes.IPv4
+	}
+	if res.MaxPrefixes.IPv6 == nil {
+		res.MaxPrefixes.IPv6 = t.MaxPrefixes.IPv6
+	}
+	res.EBGPMultiHop = n.EBGPMultiHop || t.EBGPMultiHop
+	res.ActivateEVPN = n.ActivateEVPN || t.ActivateEVPN
+	res.ToAdvertise = mergeAdvertise(t.ToAdvertise, n.ToAdvertise)
+	res.ToReceive = v1beta1.Receive{
+		Allowed: mergeAllowedPrefixes(t.ToReceive.Allowed, n.ToReceive.Allowed),
+	}
+	res.ToReceive.Policies = append(res.ToReceive.Policies, t.ToReceive.Policies...)
+	res.ToReceive.Policies = append(res.ToReceive.Policies, n.ToReceive.Policies...)
+	return res
+}
+
+func mergeAdvertise(a, b v1beta1.Advertise) v1beta1.Advertise {
+	res := v1beta1.Advertise{
+		Allowed: mergeAllowedPrefixes(a.Allowed, b.Allowed),
+	}
+	res.PrefixesWithLocalPref = append(res.PrefixesWithLocalPref, a.PrefixesWithLocalPref...)
+	res.PrefixesWithLocalPref = append(res.PrefixesWithLocalPref, b.PrefixesWithLocalPref...)
+	res.PrefixesWithCommunity = append(res.PrefixesWithCommunity, a.PrefixesWithCommunity...)
+	res.PrefixesWithCommunity = append(res.PrefixesWithCommunity, b.PrefixesWithCommunity...)
+	res.PrefixesWithASPathPrepend = append(res.PrefixesWithASPathPrepend, a.PrefixesWithASPathPrepend...)
+	res.PrefixesWithASPathPrepend = append(res.PrefixesWithASPathPrepend, b.PrefixesWithASPathPrepend...)
+	res.PrefixesWithMED = append(res.PrefixesWithMED, a.PrefixesWithMED...)
+	res.PrefixesWithMED = append(res.PrefixesWithMED, b.PrefixesWithMED...)
+	return res
+}
+
+func mergeAllowedPrefixes(a, b v1beta1.AllowedPrefixes) v1beta1.AllowedPrefixes {
+	res := v1beta1.AllowedPrefixes{Mode: b.Mode}
+	if a.Mode == v1beta1.AllowAll {
+		res.Mode = v1beta1.AllowAll
+	}
+	res.Prefixes = append(res.Prefixes, a.Prefixes...)
+	res.Prefixes = append(res.Prefixes, b.Prefixes...)
+	res.Ranges = append(res.Ranges, a.Ranges...)
+	res.Ranges = append(res.Ranges, b.Ranges...)
+	return res
+}
+
+// templateKey returns the key identifying a neighbor among the ones of all the routers.
+func templateKey(vrf string, n v1beta1.Neighbor) string {
+	if n.Interface != "" {
+		return fmt.Sprintf("%s/interface:%s", vrf, n.Interface)
+	}
+	return fmt.Sprintf("%s/%s", vrf, n.Address)
+}
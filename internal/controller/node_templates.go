@@ -0,0 +1,295 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var (
+	// nodeTemplateRegex matches the ${...} node templates in a field.
+	nodeTemplateRegex = regexp.MustCompile(`\$\{([^}]*)\}`)
+	// nodeExpressionRegex matches the expression of a node template,
+	// i.e. node.internalIPv4 or node.labels['rack'].
+	nodeExpressionRegex = regexp.MustCompile(`^node\.([A-Za-z0-9]+)(?:\['([^']+)'\])?$`)
+	// nodeAddressTemplateRegex matches the node templates resolving to
+	// the addresses of the node.
+	nodeAddressTemplateRegex = regexp.MustCompile(`\$\{\s*node\.internalIPv[46]\s*\}`)
+)
+
+// resolveNodeTemplatesFor resolves the node templates of the given configurations
+// against the given node, leaving out the configurations that can't be resolved.
+func resolveNodeTemplatesFor(cfgs []v1beta1.FRRConfiguration, node *corev1.Node) ([]v1beta1.FRRConfiguration, []excludedConfig) {
+	resolved := []v1beta1.FRRConfiguration{}
+	excluded := []excludedConfig{}
+	for _, cfg := range cfgs {
+		res, err := resolveNodeTemplates(cfg, node)
+		if err != nil {
+			excluded = append(excluded, excludedConfig{
+				config: cfg,
+				err:    fmt.Errorf("failed to resolve the node templates of FRRConfiguration %s/%s: %w", cfg.Namespace, cfg.Name, err),
+			})
+			continue
+		}
+		resolved = append(resolved, res)
+	}
+	return resolved, excluded
+}
+
+// hasNodeTemplates tells if any of the fields of the given configuration
+// must be resolved against the node, including the pod CIDRs to advertise.
+func hasNodeTemplates(cfg v1beta1.FRRConfiguration) bool {
+	for _, r := range cfg.Spec.BGP.Routers {
+		if r.ASNTemplate != "" || r.AdvertisePodCIDRs || nodeTemplateRegex.MatchString(r.ID) {
+			return true
+		}
+		for _, p := range r.Prefixes {
+			if nodeTemplateRegex.MatchString(p) {
+				return true
+			}
+		}
+		for _, n := range r.Neighbors {
+			if nodeTemplateRegex.MatchString(n.Address) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeAnnotationsIn returns the keys of the node annotations referenced by
+// the node templates of the given configurations.
+func nodeAnnotationsIn(cfgs []v1beta1.FRRConfiguration) sets.Set[string] {
+	res := sets.New[string]()
+	for _, cfg := range cfgs {
+		for _, r := range cfg.Spec.BGP.Routers {
+			fields := append([]string{r.ASNTemplate, r.ID}, r.Prefixes...)
+			for _, n := range r.Neighbors {
+				fields = append(fields, n.Address)
+			}
+			for _, f := range fields {
+				for _, m := range nodeTemplateRegex.FindAllStringSubmatch(f, -1) {
+					e := nodeExpressionRegex.FindStringSubmatch(strings.TrimSpace(m[1]))
+					if e != nil && e[1] == "annotations" && e[2] != "" {
+						res.Insert(e[2])
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// resolveNodeTemplates returns a copy of the given configuration where the node
+// templates are replaced with the values of the given node, and the pod CIDRs of
+// the node are added to the prefixes of the routers advertising them.
+// When the node is nil, the templates are only validated and left as they are.
+func resolveNodeTemplates(cfg v1beta1.FRRConfiguration, node *corev1.Node) (v1beta1.FRRConfiguration, error) {
+	res := *cfg.DeepCopy()
+	for i := range res.Spec.BGP.Routers {
+		r := &res.Spec.BGP.Routers[i]
+		err := resolveRouterTemplates(r, node)
+		if err != nil {
+			return v1beta1.FRRConfiguration{}, fmt.Errorf("router %d-%s: %w", r.ASN, r.VRF, err)
+		}
+	}
+	return res, nil
+}
+
+func resolveRouterTemplates(r *v1beta1.Router, node *corev1.Node) error {
+	if r.ASNTemplate != "" {
+		if r.ASN != 0 {
+			return fmt.Errorf("asn and asnTemplate are mutually exclusive")
+		}
+		asn, err := resolveNodeTemplate(r.ASNTemplate, node)
+		if err != nil {
+			return fmt.Errorf("invalid asnTemplate %s: %w", r.ASNTemplate, err)
+		}
+		if node != nil {
+			value, err := strconv.ParseUint(asn, 10, 32)
+			if err != nil {
+				return fmt.Errorf("asnTemplate %s resolved to invalid asn %s", r.ASNTemplate, asn)
+			}
+			r.ASN = uint32(value)
+			r.ASNTemplate = ""
+		}
+	}
+
+	id, err := resolveNodeTemplate(r.ID, node)
+	if err != nil {
+		return fmt.Errorf("invalid id %s: %w", r.ID, err)
+	}
+	r.ID = id
+
+	prefixes := []string{}
+	for _, p := range r.Prefixes {
+		expanded, err := expandNodeTemplate(p, node)
+		if err != nil {
+			return fmt.Errorf("invalid prefix %s: %w", p, err)
+		}
+		prefixes = append(prefixes, expanded...)
+	}
+	// A node without pod CIDRs (i.e. not assigned yet) has nothing extra to advertise.
+	if r.AdvertisePodCIDRs && node != nil {
+		listed := sets.New(prefixes...)
+		for _, c := range podCIDRsOf(node) {
+			if !listed.Has(c) {
+				prefixes = append(prefixes, c)
+			}
+		}
+		r.AdvertisePodCIDRs = false
+	}
+	if len(prefixes) > 0 || r.Prefixes != nil {
+		r.Prefixes = prefixes
+	}
+
+	for i := range r.Neighbors {
+		n := &r.Neighbors[i]
+		address, err := resolveNodeTemplate(n.Address, node)
+		if err != nil {
+			return fmt.Errorf("invalid address %s for neighbor: %w", n.Address, err)
+		}
+		n.Address = address
+	}
+	return nil
+}
+
+// resolveNodeTemplate replaces the node templates of the given field, which must
+// resolve to a single value.
+func resolveNodeTemplate(field string, node *corev1.Node) (string, error) {
+	for _, m := range nodeTemplateRegex.FindAllStringSubmatch(field, -1) {
+		if isMultiValueTemplate(m[1]) {
+			return "", fmt.Errorf("%s resolves to multiple values", m[0])
+		}
+	}
+	res, err := expandNodeTemplate(field, node)
+	if err != nil {
+		return "", err
+	}
+	return res[0], nil
+}
+
+// expandNodeTemplate replaces the node templates of the given field. A template
+// resolving to multiple values (i.e. ${node.podCIDRs}) must be the whole field,
+// which is expanded to one value per node value.
+func expandNodeTemplate(field string, node *corev1.Node) ([]string, error) {
+	matches := nodeTemplateRegex.FindAllStringSubmatchIndex(field, -1)
+
+	var res strings.Builder
+	last := 0
+	for _, m := range matches {
+		expression := field[m[2]:m[3]]
+		values, err := nodeTemplateValues(expression, node)
+		if err != nil {
+			return nil, err
+		}
+		if isMultiValueTemplate(expression) {
+			if len(matches) != 1 || m[0] != 0 || m[1] != len(field) {
+				return nil, fmt.Errorf("%s resolves to multiple values and must be the whole field", field[m[0]:m[1]])
+			}
+			if node == nil {
+				return []string{field}, nil
+			}
+			return values, nil
+		}
+		if node == nil {
+			continue
+		}
+		res.WriteString(field[last:m[0]])
+		res.WriteString(values[0])
+		last = m[1]
+	}
+	if node == nil {
+		return []string{field}, nil
+	}
+	res.WriteString(field[last:])
+	return []string{res.String()}, nil
+}
+
+// isMultiValueTemplate tells if the given node template expression
+// may resolve to multiple values.
+func isMultiValueTemplate(expression string) bool {
+	return strings.TrimSpace(expression) == "node.podCIDRs"
+}
+
+// nodeTemplateValues returns the values the given node template expression
+// resolves to. When the node is nil, the expression is only validated.
+func nodeTemplateValues(expression string, node *corev1.Node) ([]string, error) {
+	m := nodeExpressionRegex.FindStringSubmatch(strings.TrimSpace(expression))
+	if m == nil {
+		return nil, fmt.Errorf("invalid node template ${%s}", expression)
+	}
+	name, key := m[1], m[2]
+
+	switch name {
+	case "labels", "annotations":
+		if key == "" {
+			return nil, fmt.Errorf("node template ${%s} must specify a key", expression)
+		}
+	case "internalIPv4", "internalIPv6", "podCIDRs":
+		if key != "" {
+			return nil, fmt.Errorf("node template ${%s} does not accept a key", expression)
+		}
+	default:
+		return nil, fmt.Errorf("unknown node template ${%s}", expression)
+	}
+
+	if node == nil {
+		return nil, nil
+	}
+
+	switch name {
+	case "internalIPv4":
+		ip, err := nodeInternalIP(node, ipfamily.IPv4)
+		if err != nil {
+			return nil, err
+		}
+		return []string{ip}, nil
+	case "internalIPv6":
+		ip, err := nodeInternalIP(node, ipfamily.IPv6)
+		if err != nil {
+			return nil, err
+		}
+		return []string{ip}, nil
+	case "labels":
+		value, ok := node.Labels[key]
+		if !ok {
+			return nil, fmt.Errorf("node %s has no label %s", node.Name, key)
+		}
+		return []string{value}, nil
+	case "annotations":
+		value, ok := node.Annotations[key]
+		if !ok {
+			return nil, fmt.Errorf("node %s has no annotation %s", node.Name, key)
+		}
+		return []string{value}, nil
+	}
+
+	return nodePodCIDRs(node)
+}
+
+// nodePodCIDRs returns the pod CIDRs assigned to the given node, failing
+// if there are none.
+func nodePodCIDRs(node *corev1.Node) ([]string, error) {
+	cidrs := podCIDRsOf(node)
+	if len(cidrs) == 0 {
+		return nil, fmt.Errorf("node %s has no pod CIDRs", node.Name)
+	}
+	return cidrs, nil
+}
+
+// podCIDRsOf returns the pod CIDRs assigned to the given node, if any.
+func podCIDRsOf(node *corev1.Node) []string {
+	if len(node.Spec.PodCIDRs) == 0 && node.Spec.PodCIDR != "" {
+		return []string{node.Spec.PodCIDR}
+	}
+	return node.Spec.PodCIDRs
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestResolveNodeTemplates(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node1",
+			Labels:      map[string]string{"rack": "a", "tor-address": "192.0.2.1", "asn": "65010"},
+			Annotations: map[string]string{"example.com/router-id": "10.0.0.1"},
+		},
+		Spec: corev1.NodeSpec{
+			PodCIDRs: []string{"10.244.1.0/24", "fd00:10:244:1::/64"},
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "192.0.2.10"},
+				{Type: corev1.NodeInternalIP, Address: "2001:db8::10"},
+			},
+		},
+	}
+	withRouter := func(r v1beta1.Router) v1beta1.FRRConfiguration {
+		return v1beta1.FRRConfiguration{
+			Spec: v1beta1.FRRConfigurationSpec{
+				BGP: v1beta1.BGPConfig{
+					Routers: []v1beta1.Router{r},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		router   v1beta1.Router
+		node     *corev1.Node
+		expected v1beta1.Router
+		err      bool
+	}{
+		{
+			name: "No templates",
+			router: v1beta1.Router{ASN: 65001, ID: "10.0.0.1", Prefixes: []string{"192.168.2.0/24"},
+				Neighbors: []v1beta1.Neighbor{{Address: "192.0.2.1"}}},
+			node: node,
+			expected: v1beta1.Router{ASN: 65001, ID: "10.0.0.1", Prefixes: []string{"192.168.2.0/24"},
+				Neighbors: []v1beta1.Neighbor{{Address: "192.0.2.1"}}},
+		},
+		{
+			name: "All the templates",
+			router: v1beta1.Router{
+				ASNTemplate: "${node.labels['asn']}",
+				ID:          "${node.annotations['example.com/router-id']}",
+				Prefixes:    []string{"${node.podCIDRs}", "192.168.${ node.labels['rack-id'] }.0/24"},
+				Neighbors: []v1beta1.Neighbor{
+					{Address: "${node.labels['tor-address']}"},
+					{Address: "${node.internalIPv6}"},
+				},
+			},
+			node: func() *corev1.Node {
+				n := node.DeepCopy()
+				n.Labels["rack-id"] = "3"
+				return n
+			}(),
+			expected: v1beta1.Router{
+				ASN:      65010,
+				ID:       "10.0.0.1",
+				Prefixes: []string{"10.244.1.0/24", "fd00:10:244:1::/64", "192.168.3.0/24"},
+				Neighbors: []v1beta1.Neighbor{
+					{Address: "192.0.2.1"},
+					{Address: "2001:db8::10"},
+				},
+			},
+		},
+		{
+			name:     "Router id from the internal ip",
+			router:   v1beta1.Router{ASN: 65001, ID: "${node.internalIPv4}"},
+			node:     node,
+			expected: v1beta1.Router{ASN: 65001, ID: "192.0.2.10"},
+		},
+		{
+			name:     "Validating only, node not available",
+			router:   v1beta1.Router{ASNTemplate: "${node.labels['asn']}", Prefixes: []string{"${node.podCIDRs}"}},
+			node:     nil,
+			expected: v1beta1.Router{ASNTemplate: "${node.labels['asn']}", Prefixes: []string{"${node.podCIDRs}"}},
+		},
+		{
+			name:     "Advertising the pod CIDRs",
+			router:   v1beta1.Router{ASN: 65001, AdvertisePodCIDRs: true},
+			node:     node,
+			expected: v1beta1.Router{ASN: 65001, Prefixes: []string{"10.244.1.0/24", "fd00:10:244:1::/64"}},
+		},
+		{
+			name:     "Advertising the pod CIDRs, already listed in the prefixes",
+			router:   v1beta1.Router{ASN: 65001, Prefixes: []string{"192.168.2.0/24", "10.244.1.0/24"}, AdvertisePodCIDRs: true},
+			node:     node,
+			expected: v1beta1.Router{ASN: 65001, Prefixes: []string{"192.168.2.0/24", "10.244.1.0/24", "fd00:10:244:1::/64"}},
+		},
+		{
+			name:   "Advertising the pod CIDRs, single pod CIDR",
+			router: v1beta1.Router{ASN: 65001, AdvertisePodCIDRs: true},
+			node: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+				Spec: corev1.NodeSpec{PodCIDR: "10.244.2.0/24"}},
+			expected: v1beta1.Router{ASN: 65001, Prefixes: []string{"10.244.2.0/24"}},
+		},
+		{
+			name:     "Advertising the pod CIDRs, node not available",
+			router:   v1beta1.Router{ASN: 65001, AdvertisePodCIDRs: true},
+			node:     nil,
+			expected: v1beta1.Router{ASN: 65001, AdvertisePodCIDRs: true},
+		},
+		{
+			name:     "Advertising the pod CIDRs of a node without pod CIDRs",
+			router:   v1beta1.Router{ASN: 65001, Prefixes: []string{"192.168.2.0/24"}, AdvertisePodCIDRs: true},
+			node:     &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+			expected: v1beta1.Router{ASN: 65001, Prefixes: []string{"192.168.2.0/24"}},
+		},
+		{
+			name:   "Both asn and asn template",
+			router: v1beta1.Router{ASN: 65001, ASNTemplate: "${node.labels['asn']}"},
+			node:   node,
+			err:    true,
+		},
+		{
+			name:   "Asn template resolving to an invalid asn",
+			router: v1beta1.Router{ASNTemplate: "${node.labels['rack']}"},
+			node:   node,
+			err:    true,
+		},
+		{
+			name:   "Missing label",
+			router: v1beta1.Router{ASN: 65001, Neighbors: []v1beta1.Neighbor{{Address: "${node.labels['missing']}"}}},
+			node:   node,
+			err:    true,
+		},
+		{
+			name:   "Unknown template",
+			router: v1beta1.Router{ASN: 65001, ID: "${node.externalIP}"},
+			node:   nil,
+			err:    true,
+		},
+		{
+			name:   "Labels without a key",
+			router: v1beta1.Router{ASN: 65001, ID: "${node.labels}"},
+			node:   nil,
+			err:    true,
+		},
+		{
+			name:   "Pod CIDRs in a single value field",
+			router: v1beta1.Router{ASN: 65001, ID: "${node.podCIDRs}"},
+			node:   nil,
+			err:    true,
+		},
+		{
+			name:   "Pod CIDRs not being the whole prefix",
+			router: v1beta1.Router{ASN: 65001, Prefixes: []string{"x${node.podCIDRs}"}},
+			node:   nil,
+			err:    true,
+		},
+		{
+			name:   "Node without pod CIDRs",
+			router: v1beta1.Router{ASN: 65001, Prefixes: []string{"${node.podCIDRs}"}},
+			node:   &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := resolveNodeTemplates(withRouter(test.router), test.node)
+			if test.err && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if test.err {
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !cmp.Equal(withRouter(test.expected), res) {
+				t.Fatalf("unexpected result (-want +got)\n%s", cmp.Diff(withRouter(test.expected), res))
+			}
+		})
+	}
+}
+
+func TestNodeAnnotationsIn(t *testing.T) {
+	cfgs := []v1beta1.FRRConfiguration{
+		{
+			Spec: v1beta1.FRRConfigurationSpec{
+				BGP: v1beta1.BGPConfig{
+					Routers: []v1beta1.Router{
+						{
+							ASNTemplate: "${node.annotations['example.com/asn']}",
+							ID:          "${node.labels['router-id']}",
+							Prefixes:    []string{"192.168.${ node.annotations['example.com/rack'] }.0/24"},
+							Neighbors: []v1beta1.Neighbor{
+								{Address: "${node.annotations['example.com/tor']}"},
+								{Address: "${node.internalIPv4}"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Spec: v1beta1.FRRConfigurationSpec{
+				BGP: v1beta1.BGPConfig{
+					Routers: []v1beta1.Router{
+						{ASN: 65001, ID: "${node.annotations['example.com/router-id']}"},
+					},
+				},
+			},
+		},
+	}
+
+	res := sets.List(nodeAnnotationsIn(cfgs))
+	expected := []string{"example.com/asn", "example.com/rack", "example.com/router-id", "example.com/tor"}
+	if !cmp.Equal(expected, res) {
+		t.Fatalf("unexpected result (-want +got)\n%s", cmp.Diff(expected, res))
+	}
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/frr"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+)
+
+// maxOSPFKeyLength is the maximum length of an OSPF MD5 key.
+const maxOSPFKeyLength = 16
+
+func ospfRouterToFRR(r v1beta1.OSPFRouter, passwordSecrets map[string]corev1.Secret) (*frr.OSPFRouterConfig, error) {
+	res := &frr.OSPFRouterConfig{
+		RouterID:   r.ID,
+		VRF:        r.VRF,
+		Interfaces: []frr.OSPFInterface{},
+	}
+
+	interfaces := map[string]*frr.OSPFInterface{}
+	for _, a := range r.Areas {
+		area, err := ospfAreaFor(a.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range a.Interfaces {
+			if _, ok := interfaces[i.Name]; ok {
+				return nil, fmt.Errorf("interface %s specified multiple times", i.Name)
+			}
+			iface, err := ospfInterfaceToFRR(i, area, passwordSecrets)
+			if err != nil {
+				return nil, fmt.Errorf("invalid interface %s: %w", i.Name, err)
+			}
+			interfaces[i.Name] = iface
+		}
+	}
+	res.Interfaces = sortMap(interfaces)
+
+	prefixes := sets.New[string]()
+	for _, p := range r.Prefixes {
+		if ipfamily.ForCIDRString(p) != ipfamily.IPv4 {
+			return nil, fmt.Errorf("invalid prefix %s: only ipv4 prefixes can be redistributed", p)
+		}
+		prefixes.Insert(p)
+	}
+	res.Prefixes = sets.List(prefixes)
+
+	return res, nil
+}
+
+func ospfInterfaceToFRR(i v1beta1.OSPFInterface, area string, passwordSecrets map[string]corev1.Secret) (*frr.OSPFInterface, error) {
+	if i.Name == "" {
+		return nil, fmt.Errorf("interface with no name specified")
+	}
+	if i.Cost > 65535 {
+		return nil, fmt.Errorf("cost %d must be at most 65535", i.Cost)
+	}
+	res := &frr.OSPFInterface{
+		Name:    i.Name,
+		Area:    area,
+		Cost:    i.Cost,
+		Passive: i.Passive,
+	}
+	if i.Authentication == nil {
+		return res, nil
+	}
+
+	auth := i.Authentication
+	if auth.KeyID < 1 || auth.KeyID > 255 {
+		return nil, fmt.Errorf("authentication key id %d must be between 1 and 255", auth.KeyID)
+	}
+	secret, ok := passwordSecrets[auth.PasswordSecret.Name]
+	if !ok {
+		return nil, fmt.Errorf("secret %s not found for the authentication of the interface", auth.PasswordSecret.Name)
+	}
+	key, err := passwordFromSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) > maxOSPFKeyLength {
+		return nil, fmt.Errorf("authentication key in secret %s is longer than %d characters", auth.PasswordSecret.Name, maxOSPFKeyLength)
+	}
+	res.AuthKeyID = auth.KeyID
+	res.AuthKey = key
+	return res, nil
+}
+
+// ospfAreaFor returns the given area id in the dotted decimal form.
+func ospfAreaFor(id string) (string, error) {
+	if ip := net.ParseIP(id); ip != nil && ip.To4() != nil {
+		return ip.To4().String(), nil
+	}
+	n, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid area id %s: must be in the dotted decimal or in the decimal form", id)
+	}
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, uint32(n))
+	return ip.String(), nil
+}
+
+// setOSPFSource marks the OSPF router and its interfaces as coming from the given source.
+func setOSPFSource(r *frr.OSPFRouterConfig, source string) {
+	if source == "" {
+		return
+	}
+	r.Sources = []string{source}
+	for i := range r.Interfaces {
+		r.Interfaces[i].Sources = []string{source}
+	}
+}
+
+// validateOSPFInterfaces verifies that each interface is used by one OSPF router only.
+func validateOSPFInterfaces(routers []*frr.OSPFRouterConfig) error {
+	vrfForInterface := map[string]string{}
+	for _, r := range routers {
+		for _, i := range r.Interfaces {
+			vrf, ok := vrfForInterface[i.Name]
+			if ok {
+				return fmt.Errorf("interface %s used by the ospf routers of vrfs %q and %q", i.Name, vrf, r.VRF)
+			}
+			vrfForInterface[i.Name] = r.VRF
+		}
+	}
+	return nil
+}
+
+// sortOSPFInterfaces sorts the given interfaces by name.
+func sortOSPFInterfaces(interfaces []frr.OSPFInterface) {
+	sort.Slice(interfaces, func(i, j int) bool {
+		return interfaces[i].Name < interfaces[j].Name
+	})
+}
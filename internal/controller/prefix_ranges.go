@@ -0,0 +1,112 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"net"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/frr"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+)
+
+// prefixRange is the validated version of a v1beta1.PrefixRange.
+type prefixRange struct {
+	cidr     *net.IPNet
+	ipFamily ipfamily.Family
+	ge       uint32
+	le       uint32
+	deny     bool
+}
+
+func prefixRangeFor(r v1beta1.PrefixRange) (prefixRange, error) {
+	_, cidr, err := net.ParseCIDR(r.Prefix)
+	if err != nil {
+		return prefixRange{}, fmt.Errorf("invalid prefix range %s: %w", r.Prefix, err)
+	}
+	length, bits := cidr.Mask.Size()
+	if r.GE != 0 && (r.GE <= uint32(length) || r.GE > uint32(bits)) {
+		return prefixRange{}, fmt.Errorf("invalid prefix range %s: ge %d must be greater than %d and lower than %d", r.Prefix, r.GE, length, bits+1)
+	}
+	if r.LE != 0 && (r.LE <= uint32(length) || r.LE > uint32(bits)) {
+		return prefixRange{}, fmt.Errorf("invalid prefix range %s: le %d must be greater than %d and lower than %d", r.Prefix, r.LE, length, bits+1)
+	}
+	if r.GE != 0 && r.LE != 0 && r.GE > r.LE {
+		return prefixRange{}, fmt.Errorf("invalid prefix range %s: ge %d is greater than le %d", r.Prefix, r.GE, r.LE)
+	}
+
+	return prefixRange{
+		cidr:     cidr,
+		ipFamily: ipfamily.ForCIDR(cidr),
+		ge:       r.GE,
+		le:       r.LE,
+		deny:     r.Action == v1beta1.DenyPrefix,
+	}, nil
+}
+
+// matches tells if the given prefix is matched by the range, following
+// the semantic of the FRR prefix lists.
+func (r prefixRange) matches(prefix string) bool {
+	ip, cidr, err := net.ParseCIDR(prefix)
+	if err != nil || ipfamily.ForCIDR(cidr) != r.ipFamily {
+		return false
+	}
+	if !r.cidr.Contains(ip) {
+		return false
+	}
+	length, bits := cidr.Mask.Size()
+	rangeLength, _ := r.cidr.Mask.Size()
+	minLength, maxLength := rangeLength, rangeLength
+	if r.ge != 0 {
+		minLength, maxLength = int(r.ge), bits
+	}
+	if r.le != 0 {
+		maxLength = int(r.le)
+	}
+	return length >= minLength && length <= maxLength
+}
+
+// prefixRangeFromFRR returns the range the given filter was translated from.
+func prefixRangeFromFRR(f frr.IncomingFilter) prefixRange {
+	_, cidr, _ := net.ParseCIDR(f.Prefix)
+	return prefixRange{
+		cidr:     cidr,
+		ipFamily: f.IPFamily,
+		ge:       f.GE,
+		le:       f.LE,
+		deny:     f.Deny,
+	}
+}
+
+func (r prefixRange) toFRR() frr.IncomingFilter {
+	return frr.IncomingFilter{
+		IPFamily: r.ipFamily,
+		Prefix:   r.cidr.String(),
+		GE:       r.ge,
+		LE:       r.le,
+		Deny:     r.deny,
+	}
+}
+
+func prefixRangesFor(ranges []v1beta1.PrefixRange) ([]prefixRange, error) {
+	res := make([]prefixRange, 0, len(ranges))
+	for _, r := range ranges {
+		pr, err := prefixRangeFor(r)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pr)
+	}
+	return res, nil
+}
+
+// firstMatchingRange returns the first of the given ranges matching the prefix.
+func firstMatchingRange(ranges []prefixRange, prefix string) (prefixRange, bool) {
+	for _, r := range ranges {
+		if r.matches(prefix) {
+			return r, true
+		}
+	}
+	return prefixRange{}, false
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+)
+
+func TestPrefixRangeMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        v1beta1.PrefixRange
+		prefix   string
+		expected bool
+	}{
+		{
+			name:     "no bounds, same prefix",
+			r:        v1beta1.PrefixRange{Prefix: "10.0.0.0/8"},
+			prefix:   "10.0.0.0/8",
+			expected: true,
+		},
+		{
+			name:     "no bounds, contained prefix",
+			r:        v1beta1.PrefixRange{Prefix: "10.0.0.0/8"},
+			prefix:   "10.1.0.0/16",
+			expected: false,
+		},
+		{
+			name:     "le, contained prefix",
+			r:        v1beta1.PrefixRange{Prefix: "10.0.0.0/8", LE: 24},
+			prefix:   "10.1.0.0/16",
+			expected: true,
+		},
+		{
+			name:     "le, longer prefix",
+			r:        v1beta1.PrefixRange{Prefix: "10.0.0.0/8", LE: 24},
+			prefix:   "10.1.1.1/32",
+			expected: false,
+		},
+		{
+			name:     "ge, host route",
+			r:        v1beta1.PrefixRange{Prefix: "10.0.0.0/8", GE: 32},
+			prefix:   "10.1.1.1/32",
+			expected: true,
+		},
+		{
+			name:     "ge, shorter prefix",
+			r:        v1beta1.PrefixRange{Prefix: "10.0.0.0/8", GE: 32},
+			prefix:   "10.1.0.0/16",
+			expected: false,
+		},
+		{
+			name:     "ge and le, not contained",
+			r:        v1beta1.PrefixRange{Prefix: "10.0.0.0/8", GE: 16, LE: 24},
+			prefix:   "11.1.0.0/16",
+			expected: false,
+		},
+		{
+			name:     "ipv6 range, ipv4 prefix",
+			r:        v1beta1.PrefixRange{Prefix: "::/0", LE: 128},
+			prefix:   "10.1.0.0/16",
+			expected: false,
+		},
+		{
+			name:     "ipv6 range, ge and le",
+			r:        v1beta1.PrefixRange{Prefix: "2001:db8::/32", GE: 64, LE: 128},
+			prefix:   "2001:db8:1::/64",
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := prefixRangeFor(test.r)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if r.matches(test.prefix) != test.expected {
+				t.Fatalf("expected match %t for range %v and prefix %s", test.expected, test.r, test.prefix)
+			}
+		})
+	}
+}
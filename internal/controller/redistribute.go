@@ -0,0 +1,151 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/community"
+	"github.com/metallb/frrk8s/internal/frr"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+)
+
+// redistributeToFRR translates the redistributions of a router, split by family.
+// The redistributions of the same source are merged.
+func redistributeToFRR(vrf string, r v1beta1.Redistribute, aliases map[string]string) ([]frr.Redistribution, []frr.Redistribution, error) {
+	var resV4, resV6 []frr.Redistribution
+	for _, d := range r.IPv4 {
+		redistribution, err := redistributionToFRR(d, ipfamily.IPv4, aliases)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid ipv4 redistribution of %s: %w", d.Source, err)
+		}
+		resV4, err = mergeRedistributions(vrf, resV4, []frr.Redistribution{redistribution})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, d := range r.IPv6 {
+		redistribution, err := redistributionToFRR(d, ipfamily.IPv6, aliases)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid ipv6 redistribution of %s: %w", d.Source, err)
+		}
+		resV6, err = mergeRedistributions(vrf, resV6, []frr.Redistribution{redistribution})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return resV4, resV6, nil
+}
+
+func redistributionToFRR(d v1beta1.Redistribution, family ipfamily.Family, aliases map[string]string) (frr.Redistribution, error) {
+	switch d.Source {
+	case v1beta1.RedistributeConnected, v1beta1.RedistributeKernel, v1beta1.RedistributeStatic:
+		if d.Table != 0 {
+			return frr.Redistribution{}, fmt.Errorf("table can be set only when redistributing a table")
+		}
+	case v1beta1.RedistributeTable:
+		if d.Table == 0 || d.Table > 65535 {
+			return frr.Redistribution{}, fmt.Errorf("table %d must be between 1 and 65535", d.Table)
+		}
+	default:
+		return frr.Redistribution{}, fmt.Errorf("unknown source %s", d.Source)
+	}
+
+	res := frr.Redistribution{
+		IPFamily:  family,
+		Source:    string(d.Source),
+		Table:     d.Table,
+		LocalPref: d.LocalPref,
+	}
+	ranges, err := prefixRangesFor(d.Prefixes)
+	if err != nil {
+		return frr.Redistribution{}, err
+	}
+	for _, r := range ranges {
+		if r.ipFamily != family {
+			return frr.Redistribution{}, fmt.Errorf("prefix range %s is not of the %s family", r.cidr, family)
+		}
+		res.Prefixes = append(res.Prefixes, r.toFRR())
+	}
+	for _, c := range d.Communities {
+		comm, err := communityFor(c, aliases)
+		if err != nil {
+			return frr.Redistribution{}, err
+		}
+		switch {
+		case community.IsLarge(comm):
+			res.LargeCommunities = append(res.LargeCommunities, comm.String())
+		case community.IsExtended(comm):
+			return frr.Redistribution{}, fmt.Errorf("extended community %s can't be attached to the redistributed routes", c)
+		default:
+			res.Communities = append(res.Communities, comm.String())
+		}
+	}
+	return res, nil
+}
+
+// redistributionsMatch tells if any of the given redistributions redistributes
+// the given prefix, so that it can be advertised to the neighbors.
+func redistributionsMatch(redistributions []frr.Redistribution, prefix string) bool {
+	for _, d := range redistributions {
+		if len(d.Prefixes) == 0 {
+			return true
+		}
+		ranges := make([]prefixRange, 0, len(d.Prefixes))
+		for _, f := range d.Prefixes {
+			ranges = append(ranges, prefixRangeFromFRR(f))
+		}
+		r, ok := firstMatchingRange(ranges, prefix)
+		if ok && !r.deny {
+			return true
+		}
+	}
+	return false
+}
+
+// advertiseRedistributed leaves the redistributed routes in the advertisements
+// to the neighbors only for the families the router redistributes routes of.
+func advertiseRedistributed(routers []*frr.RouterConfig) {
+	for _, r := range routers {
+		for _, n := range r.Neighbors {
+			if len(r.RedistributeV4) == 0 {
+				n.Outgoing.RedistributedV4 = nil
+			}
+			if len(r.RedistributeV6) == 0 {
+				n.Outgoing.RedistributedV6 = nil
+			}
+		}
+	}
+}
+
+// redistributedToAdvertise returns the filters selecting the redistributed routes
+// advertised to a neighbor: all of them when all the prefixes are allowed,
+// otherwise the ones matching the neighbor's ranges.
+func redistributedToAdvertise(allowed v1beta1.AllowedPrefixes) ([]frr.IncomingFilter, []frr.IncomingFilter, error) {
+	if allowed.Mode == v1beta1.AllowAll {
+		return []frr.IncomingFilter{{IPFamily: ipfamily.IPv4, Prefix: "0.0.0.0/0", LE: 32}},
+			[]frr.IncomingFilter{{IPFamily: ipfamily.IPv6, Prefix: "::/0", LE: 128}}, nil
+	}
+	ranges, err := prefixRangesFor(allowed.Ranges)
+	if err != nil {
+		return nil, nil, err
+	}
+	var resV4, resV6 []frr.IncomingFilter
+	for _, r := range ranges {
+		if r.ipFamily == ipfamily.IPv4 {
+			resV4 = append(resV4, r.toFRR())
+			continue
+		}
+		resV6 = append(resV6, r.toFRR())
+	}
+	return resV4, resV6, nil
+}
+
+// sortRedistributions sorts the given redistributions by name.
+func sortRedistributions(redistributions []frr.Redistribution) {
+	sort.Slice(redistributions, func(i, j int) bool {
+		return redistributions[i].Name() < redistributions[j].Name()
+	})
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"net"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// interfaceAddresses returns the addresses of the given local interface.
+var interfaceAddresses = func(name string) ([]net.IP, error) {
+	intf, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := intf.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	res := []net.IP{}
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		res = append(res, ipNet.IP)
+	}
+	return res, nil
+}
+
+// sourceAddressForNeighbor returns the source address to be used for the session with
+// the given neighbor, resolving it against the node when required.
+// When the resources needed to resolve it are not available, a placeholder describing
+// the selector is returned so that equivalent neighbors still compare equal.
+func sourceAddressForNeighbor(n v1beta1.Neighbor, family ipfamily.Family, resources clusterResources) (string, error) {
+	if n.SourceAddress != "" && n.SourceAddressFrom != nil {
+		return "", fmt.Errorf("sourceAddress and sourceAddressFrom are mutually exclusive")
+	}
+
+	if n.SourceAddress != "" {
+		ip := net.ParseIP(n.SourceAddress)
+		if ip == nil {
+			return "", fmt.Errorf("invalid source address %s", n.SourceAddress)
+		}
+		if ipfamily.ForAddress(ip) != family {
+			return "", fmt.Errorf("source address %s does not match the family of the neighbor address %s", n.SourceAddress, n.Address)
+		}
+		return n.SourceAddress, nil
+	}
+
+	if n.SourceAddressFrom == nil {
+		return "", nil
+	}
+
+	selector := n.SourceAddressFrom
+	switch {
+	case selector.NodeInternalIP && selector.Interface != "":
+		return "", fmt.Errorf("nodeInternalIP and interface are mutually exclusive")
+	case selector.NodeInternalIP:
+		if resources.node == nil {
+			return "node-internal-ip", nil
+		}
+		return nodeInternalIP(resources.node, family)
+	case selector.Interface != "":
+		if resources.interfaceAddresses == nil {
+			return "interface-" + selector.Interface, nil
+		}
+		return interfaceIP(selector.Interface, family, resources.interfaceAddresses)
+	}
+	return "", fmt.Errorf("sourceAddressFrom must specify either nodeInternalIP or interface")
+}
+
+// nodeInternalIP returns the first InternalIP of the given node matching the given family.
+func nodeInternalIP(node *corev1.Node, family ipfamily.Family) (string, error) {
+	for _, a := range node.Status.Addresses {
+		if a.Type != corev1.NodeInternalIP {
+			continue
+		}
+		ip := net.ParseIP(a.Address)
+		if ip == nil {
+			continue
+		}
+		if ipfamily.ForAddress(ip) == family {
+			return ip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("node %s has no %s InternalIP", node.Name, family)
+}
+
+// interfaceIP returns the first global unicast address of the given interface
+// matching the given family.
+func interfaceIP(name string, family ipfamily.Family, addresses func(string) ([]net.IP, error)) (string, error) {
+	ips, err := addresses(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the addresses of interface %s: %w", name, err)
+	}
+	for _, ip := range ips {
+		if !ip.IsGlobalUnicast() {
+			continue
+		}
+		if ipfamily.ForAddress(ip) == family {
+			return ip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("interface %s has no %s global unicast address", name, family)
+}
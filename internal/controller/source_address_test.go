@@ -0,0 +1,152 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSourceAddressForNeighbor(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "node1"},
+				{Type: corev1.NodeExternalIP, Address: "203.0.113.1"},
+				{Type: corev1.NodeInternalIP, Address: "192.0.2.10"},
+				{Type: corev1.NodeInternalIP, Address: "2001:db8::10"},
+			},
+		},
+	}
+	interfaces := func(name string) ([]net.IP, error) {
+		if name != "eth1" {
+			return nil, errors.New("interface not found")
+		}
+		return []net.IP{
+			net.ParseIP("fe80::1"),
+			net.ParseIP("192.0.3.10"),
+			net.ParseIP("2001:db8:1::10"),
+		}, nil
+	}
+	resources := clusterResources{node: node, interfaceAddresses: interfaces}
+
+	tests := []struct {
+		name      string
+		neighbor  v1beta1.Neighbor
+		resources clusterResources
+		expected  string
+		err       bool
+	}{
+		{
+			name:      "No source address",
+			neighbor:  v1beta1.Neighbor{Address: "192.0.2.1"},
+			resources: resources,
+			expected:  "",
+		},
+		{
+			name:      "Explicit source address",
+			neighbor:  v1beta1.Neighbor{Address: "192.0.2.1", SourceAddress: "192.0.2.20"},
+			resources: resources,
+			expected:  "192.0.2.20",
+		},
+		{
+			name:      "Explicit source address, family mismatch",
+			neighbor:  v1beta1.Neighbor{Address: "192.0.2.1", SourceAddress: "2001:db8::20"},
+			resources: resources,
+			err:       true,
+		},
+		{
+			name:      "Explicit source address, invalid",
+			neighbor:  v1beta1.Neighbor{Address: "192.0.2.1", SourceAddress: "192.0.2"},
+			resources: resources,
+			err:       true,
+		},
+		{
+			name: "Both source address and selector",
+			neighbor: v1beta1.Neighbor{Address: "192.0.2.1", SourceAddress: "192.0.2.20",
+				SourceAddressFrom: &v1beta1.SourceAddressSelector{NodeInternalIP: true}},
+			resources: resources,
+			err:       true,
+		},
+		{
+			name:      "Node InternalIP, ipv4",
+			neighbor:  v1beta1.Neighbor{Address: "192.0.2.1", SourceAddressFrom: &v1beta1.SourceAddressSelector{NodeInternalIP: true}},
+			resources: resources,
+			expected:  "192.0.2.10",
+		},
+		{
+			name:      "Node InternalIP, ipv6",
+			neighbor:  v1beta1.Neighbor{Address: "2001:db8::1", SourceAddressFrom: &v1beta1.SourceAddressSelector{NodeInternalIP: true}},
+			resources: resources,
+			expected:  "2001:db8::10",
+		},
+		{
+			name:      "Node InternalIP, no node",
+			neighbor:  v1beta1.Neighbor{Address: "192.0.2.1", SourceAddressFrom: &v1beta1.SourceAddressSelector{NodeInternalIP: true}},
+			resources: clusterResources{},
+			expected:  "node-internal-ip",
+		},
+		{
+			name:     "Node InternalIP, no address of the family",
+			neighbor: v1beta1.Neighbor{Address: "2001:db8::1", SourceAddressFrom: &v1beta1.SourceAddressSelector{NodeInternalIP: true}},
+			resources: clusterResources{node: &corev1.Node{
+				Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "192.0.2.10"}}},
+			}},
+			err: true,
+		},
+		{
+			name:      "Interface, ipv6 skips link local",
+			neighbor:  v1beta1.Neighbor{Address: "2001:db8::1", SourceAddressFrom: &v1beta1.SourceAddressSelector{Interface: "eth1"}},
+			resources: resources,
+			expected:  "2001:db8:1::10",
+		},
+		{
+			name:      "Interface, ipv4",
+			neighbor:  v1beta1.Neighbor{Address: "192.0.2.1", SourceAddressFrom: &v1beta1.SourceAddressSelector{Interface: "eth1"}},
+			resources: resources,
+			expected:  "192.0.3.10",
+		},
+		{
+			name:      "Interface not found",
+			neighbor:  v1beta1.Neighbor{Address: "192.0.2.1", SourceAddressFrom: &v1beta1.SourceAddressSelector{Interface: "eth2"}},
+			resources: resources,
+			err:       true,
+		},
+		{
+			name: "Both node InternalIP and interface",
+			neighbor: v1beta1.Neighbor{Address: "192.0.2.1",
+				SourceAddressFrom: &v1beta1.SourceAddressSelector{NodeInternalIP: true, Interface: "eth1"}},
+			resources: resources,
+			err:       true,
+		},
+		{
+			name:      "Empty selector",
+			neighbor:  v1beta1.Neighbor{Address: "192.0.2.1", SourceAddressFrom: &v1beta1.SourceAddressSelector{}},
+			resources: resources,
+			err:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			family := ipfamily.ForAddress(net.ParseIP(test.neighbor.Address))
+			res, err := sourceAddressForNeighbor(test.neighbor, family, test.resources)
+			if test.err && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if !test.err && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if res != test.expected {
+				t.Fatalf("expected source address %q, got %q", test.expected, res)
+			}
+		})
+	}
+}
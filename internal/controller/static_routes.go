@@ -0,0 +1,110 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/frr"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+)
+
+// staticRoutesToFRR translates the static routes of a VRF. The routes are
+// sorted by prefix, and the ones with the same prefix are merged.
+func staticRoutesToFRR(s v1beta1.StaticRoutes) (*frr.StaticRoutesConfig, error) {
+	res := &frr.StaticRoutesConfig{
+		VRF:    s.VRF,
+		Routes: []frr.StaticRoute{},
+	}
+	for _, r := range s.Routes {
+		route, err := staticRouteToFRR(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid static route to %s: %w", r.Prefix, err)
+		}
+		res.Routes, err = mergeStaticRoutes(s.VRF, res.Routes, []frr.StaticRoute{route})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func staticRouteToFRR(r v1beta1.StaticRoute) (frr.StaticRoute, error) {
+	_, cidr, err := net.ParseCIDR(r.Prefix)
+	if err != nil {
+		return frr.StaticRoute{}, fmt.Errorf("invalid prefix: %w", err)
+	}
+	if r.Distance > 255 {
+		return frr.StaticRoute{}, fmt.Errorf("distance %d must be at most 255", r.Distance)
+	}
+	res := frr.StaticRoute{
+		IPFamily:   ipfamily.ForCIDR(cidr),
+		Prefix:     cidr.String(),
+		Interface:  r.Interface,
+		Distance:   r.Distance,
+		Blackhole:  r.Blackhole,
+		BFDProfile: r.BFDProfile,
+	}
+
+	if r.Blackhole {
+		if r.NextHop != "" || r.Interface != "" || r.BFDProfile != "" {
+			return frr.StaticRoute{}, fmt.Errorf("a blackhole route can't have a next hop, an interface or a bfd profile")
+		}
+		return res, nil
+	}
+	if r.NextHop == "" && r.Interface == "" {
+		return frr.StaticRoute{}, fmt.Errorf("either a next hop or an interface must be specified for a non blackhole route")
+	}
+	if r.NextHop == "" {
+		if r.BFDProfile != "" {
+			return frr.StaticRoute{}, fmt.Errorf("bfd profile %s requires a next hop to monitor", r.BFDProfile)
+		}
+		return res, nil
+	}
+
+	nextHop := net.ParseIP(r.NextHop)
+	if nextHop == nil {
+		return frr.StaticRoute{}, fmt.Errorf("invalid next hop %s", r.NextHop)
+	}
+	if ipfamily.ForAddress(nextHop) != res.IPFamily {
+		return frr.StaticRoute{}, fmt.Errorf("next hop %s is not of the same family of the prefix", r.NextHop)
+	}
+	res.NextHop = nextHop.String()
+	return res, nil
+}
+
+// setStaticRoutesSource marks the static routes as coming from the given source.
+func setStaticRoutesSource(s *frr.StaticRoutesConfig, source string) {
+	if source == "" {
+		return
+	}
+	for i := range s.Routes {
+		s.Routes[i].Sources = []string{source}
+	}
+}
+
+// validateStaticRoutesBFDProfiles verifies that the bfd profiles the static routes
+// reference exist.
+func validateStaticRoutesBFDProfiles(staticRoutes []*frr.StaticRoutesConfig, profiles map[string]*frr.BFDProfile) error {
+	for _, s := range staticRoutes {
+		for _, r := range s.Routes {
+			if r.BFDProfile == "" {
+				continue
+			}
+			if _, ok := profiles[r.BFDProfile]; !ok {
+				return fmt.Errorf("static route to %s at vrf %s references non existing bfd profile %s", r.Prefix, s.VRF, r.BFDProfile)
+			}
+		}
+	}
+	return nil
+}
+
+// sortStaticRoutes sorts the given routes by prefix.
+func sortStaticRoutes(routes []frr.StaticRoute) {
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Prefix < routes[j].Prefix
+	})
+}
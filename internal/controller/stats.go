@@ -38,9 +38,16 @@ var (
 		Name:      "config_stale_bool",
 		Help:      "1 if running on a stale configuration, because the latest config failed to load.",
 	})
+
+	configExcluded = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: Subsystem,
+		Name:      "config_excluded_bool",
+		Help:      "1 if the FRRConfiguration was excluded from the node's configuration because it is invalid or conflicting.",
+	}, []string{"namespace", "name"})
 )
 
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(updates, updateErrors, configLoaded, configStale)
+	metrics.Registry.MustRegister(updates, updateErrors, configLoaded, configStale, configExcluded)
 }
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier:Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	v1beta1 "github.com/metallb/frrk8s/api/v1beta1"
+	"github.com/metallb/frrk8s/internal/community"
+	"github.com/metallb/frrk8s/internal/frr"
+	"github.com/metallb/frrk8s/internal/ipfamily"
+)
+
+// defaultVRF is the name the default VRF is referenced with when importing routes.
+const defaultVRF = "default"
+
+// importsToFRR translates the imports of the router of the given VRF. The imports
+// are sorted by VRF, and the ones from the same VRF are merged.
+func importsToFRR(vrf string, imports []v1beta1.Import) ([]frr.VRFImport, error) {
+	routerVRF := vrf
+	if routerVRF == "" {
+		routerVRF = defaultVRF
+	}
+	var res []frr.VRFImport
+	for _, i := range imports {
+		if i.VRF == "" {
+			return nil, fmt.Errorf("import with no vrf specified")
+		}
+		if i.VRF == routerVRF {
+			return nil, fmt.Errorf("can't import routes from the router's own vrf %s", i.VRF)
+		}
+		ranges, err := prefixRangesFor(i.Prefixes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid import from vrf %s: %w", i.VRF, err)
+		}
+		imp := frr.VRFImport{
+			VRF:        i.VRF,
+			All:        len(ranges) == 0,
+			PrefixesV4: []frr.IncomingFilter{},
+			PrefixesV6: []frr.IncomingFilter{},
+		}
+		for _, r := range ranges {
+			if r.ipFamily == ipfamily.IPv4 {
+				imp.PrefixesV4 = append(imp.PrefixesV4, r.toFRR())
+				continue
+			}
+			imp.PrefixesV6 = append(imp.PrefixesV6, r.toFRR())
+		}
+		res = mergeImports(res, []frr.VRFImport{imp})
+	}
+	return res, nil
+}
+
+// vpnToFRR translates the VPN leaking settings of a router.
+func vpnToFRR(vpn *v1beta1.VPNLeak) (*frr.VPNLeak, error) {
+	if vpn == nil {
+		return nil, nil
+	}
+	if vpn.RouteDistinguisher != "" {
+		if err := validateRouteTarget(vpn.RouteDistinguisher); err != nil {
+			return nil, fmt.Errorf("invalid route distinguisher: %w", err)
+		}
+	}
+	if len(vpn.ExportRouteTargets) > 0 && vpn.RouteDistinguisher == "" {
+		return nil, fmt.Errorf("exporting routes to the vpn table requires a route distinguisher")
+	}
+	for _, rt := range append(vpn.ImportRouteTargets, vpn.ExportRouteTargets...) {
+		if err := validateRouteTarget(rt); err != nil {
+			return nil, fmt.Errorf("invalid route target: %w", err)
+		}
+	}
+	return &frr.VPNLeak{
+		RouteDistinguisher: vpn.RouteDistinguisher,
+		ImportRouteTargets: sets.List(sets.New(vpn.ImportRouteTargets...)),
+		ExportRouteTargets: sets.List(sets.New(vpn.ExportRouteTargets...)),
+	}, nil
+}
+
+// validateRouteTarget verifies the given value is in the ASN:NN or IP:NN form,
+// shared by the route targets and the route distinguishers.
+func validateRouteTarget(rt string) error {
+	_, err := community.New(community.RouteTarget + ":" + rt)
+	if err != nil {
+		return fmt.Errorf("%s must be in the ASN:NN or IP:NN form: %w", rt, err)
+	}
+	return nil
+}
+
+// sortImports sorts the given imports by VRF.
+func sortImports(imports []frr.VRFImport) {
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].VRF < imports[j].VRF
+	})
+}
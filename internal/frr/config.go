@@ -10,6 +10,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"syscall"
 	"text/template"
 	"time"
@@ -27,12 +28,55 @@ var (
 	templates embed.FS
 )
 
+// redistributedTag is the tag set on the routes redistributed into BGP, for the
+// route maps of the neighbors to tell them from the routes received from the others.
+const redistributedTag = 4242
+
 type Config struct {
 	Loglevel    string
 	Hostname    string
 	Routers     []*RouterConfig
-	BFDProfiles []BFDProfile
-	ExtraConfig string
+	OSPFRouters []*OSPFRouterConfig
+	// StaticRoutes are the static routes grouped by VRF, sorted by VRF.
+	StaticRoutes []*StaticRoutesConfig
+	BFDProfiles  []BFDProfile
+	ExtraConfig  string
+}
+
+// Redacted returns a copy of the config where the neighbors' passwords and
+// the OSPF authentication keys are redacted, suitable to be logged or exposed.
+func (c *Config) Redacted() *Config {
+	res := *c
+	routers := make([]*RouterConfig, 0, len(c.Routers))
+	for _, r := range c.Routers {
+		neighbors := make([]*NeighborConfig, 0, len(r.Neighbors))
+		for _, n := range r.Neighbors {
+			n1 := *n
+			if n1.Password != "" {
+				n1.Password = "<retracted>"
+			}
+			neighbors = append(neighbors, &n1)
+		}
+		r1 := *r
+		r1.Neighbors = neighbors
+		routers = append(routers, &r1)
+	}
+	res.Routers = routers
+	ospfRouters := make([]*OSPFRouterConfig, 0, len(c.OSPFRouters))
+	for _, r := range c.OSPFRouters {
+		interfaces := make([]OSPFInterface, 0, len(r.Interfaces))
+		for _, i := range r.Interfaces {
+			if i.AuthKey != "" {
+				i.AuthKey = "<retracted>"
+			}
+			interfaces = append(interfaces, i)
+		}
+		r1 := *r
+		r1.Interfaces = interfaces
+		ospfRouters = append(ospfRouters, &r1)
+	}
+	res.OSPFRouters = ospfRouters
+	return &res
 }
 
 type reloadEvent struct {
@@ -47,6 +91,150 @@ type RouterConfig struct {
 	VRF          string
 	IPV4Prefixes []string
 	IPV6Prefixes []string
+	// ListenLimit is the maximum number of sessions accepted from
+	// the dynamic neighbors. FRR's default is used when not set.
+	ListenLimit     uint32
+	GracefulRestart *GracefulRestart
+	// Imports are the VRFs the router imports the routes from, sorted by VRF.
+	Imports []VRFImport
+	VPN     *VPNLeak
+	EVPN    *EVPN
+	// RedistributeV4 and RedistributeV6 are the redistributed sources,
+	// sorted by name.
+	RedistributeV4 []Redistribution
+	RedistributeV6 []Redistribution
+	// Sources are the namespace/name of the configurations the router comes from.
+	Sources []string
+	// PrefixSources maps each prefix to the configurations advertising it.
+	PrefixSources map[string][]string
+}
+
+// AllRedistributions returns the redistributions of both the families.
+func (r *RouterConfig) AllRedistributions() []Redistribution {
+	return append(append([]Redistribution{}, r.RedistributeV4...), r.RedistributeV6...)
+}
+
+// Redistribution represents the routes of a source redistributed into BGP
+// for an address family. When Prefixes is empty, all the routes are
+// redistributed.
+type Redistribution struct {
+	IPFamily ipfamily.Family
+	// Source is one of connected, kernel, static and table.
+	Source           string
+	Table            uint32
+	Prefixes         []IncomingFilter
+	Communities      []string
+	LargeCommunities []string
+	LocalPref        uint32
+	ASPathPrepend    *ASPathPrepend
+	Sources          []string
+}
+
+// Name returns the name of the redistributed source, i.e. connected or table-10.
+func (r Redistribution) Name() string {
+	if r.Table != 0 {
+		return fmt.Sprintf("%s-%d", r.Source, r.Table)
+	}
+	return r.Source
+}
+
+// GracefulRestart holds the graceful restart settings of a router. The timers
+// are in seconds, and FRR's defaults are used when not set.
+type GracefulRestart struct {
+	// Mode is one of "restart", "helper" or "disabled".
+	Mode          string
+	RestartTime   uint64
+	StalePathTime uint64
+	LLGRStaleTime uint64
+}
+
+// VRFImport represents the routes imported from another VRF. When All is
+// false, only the routes permitted by the prefixes are imported.
+type VRFImport struct {
+	VRF        string
+	All        bool
+	PrefixesV4 []IncomingFilter
+	PrefixesV6 []IncomingFilter
+	Sources    []string
+}
+
+// VPNLeak holds the settings of the leaking of routes through the VPN table.
+type VPNLeak struct {
+	RouteDistinguisher string
+	ImportRouteTargets []string
+	ExportRouteTargets []string
+}
+
+// EVPN holds the settings of the l2vpn evpn address family of a router.
+type EVPN struct {
+	AdvertiseAllVNI      bool
+	VNI                  uint32
+	RouteDistinguisher   string
+	ImportRouteTargets   []string
+	ExportRouteTargets   []string
+	AdvertiseIPv4Unicast bool
+	AdvertiseIPv6Unicast bool
+}
+
+// ImportRouteMap returns the name of the route map filtering the routes
+// imported from other VRFs.
+func (r *RouterConfig) ImportRouteMap() string {
+	vrf := r.VRF
+	if vrf == "" {
+		vrf = "default"
+	}
+	return fmt.Sprintf("%s-import", vrf)
+}
+
+type OSPFRouterConfig struct {
+	RouterID string
+	VRF      string
+	// Interfaces are sorted by name.
+	Interfaces []OSPFInterface
+	// Prefixes are the IPv4 prefixes redistributed as external routes.
+	Prefixes []string
+	Sources  []string
+}
+
+// RedistributeRouteMap returns the name of the route map selecting the
+// redistributed prefixes.
+func (r *OSPFRouterConfig) RedistributeRouteMap() string {
+	vrf := r.VRF
+	if vrf == "" {
+		vrf = "default"
+	}
+	return fmt.Sprintf("ospf-%s-redistribute", vrf)
+}
+
+type OSPFInterface struct {
+	Name string
+	// Area is in the dotted decimal form.
+	Area    string
+	Cost    uint32
+	Passive bool
+	// AuthKeyID and AuthKey are set when the MD5 authentication is enabled.
+	AuthKeyID uint32
+	AuthKey   string
+	Sources   []string
+}
+
+type StaticRoutesConfig struct {
+	VRF string
+	// Routes are sorted by prefix.
+	Routes []StaticRoute
+}
+
+// StaticRoute is a static route. Blackhole routes have neither a next
+// hop nor an interface.
+type StaticRoute struct {
+	IPFamily   ipfamily.Family
+	Prefix     string
+	NextHop    string
+	Interface  string
+	Distance   uint32
+	Blackhole  bool
+	BFDProfile string
+	Sources    []string
 }
 
 type BFDProfile struct {
@@ -61,42 +249,113 @@ type BFDProfile struct {
 }
 
 type NeighborConfig struct {
-	IPFamily      ipfamily.Family
-	Name          string
-	ASN           uint32
-	SrcAddr       string
-	Addr          string
+	IPFamily ipfamily.Family
+	Name     string
+	ASN      uint32
+	// DynamicASN is either "internal" or "external", and is used
+	// instead of ASN when set.
+	DynamicASN string
+	SrcAddr    string
+	Addr       string
+	// Iface is the interface used by unnumbered sessions, set instead of Addr.
+	Iface string
+	// ListenRange is set for dynamic neighbors, instead of Addr. The neighbor is
+	// rendered as a peer-group accepting sessions from the range.
+	ListenRange   string
+	SessionLimit  uint32
 	Port          uint16
 	HoldTime      uint64
 	KeepaliveTime uint64
 	Password      string
 	BFDProfile    string
 	EBGPMultiHop  bool
-	VRFName       string
-	Incoming      AllowedIn
-	Outgoing      AllowedOut
+	MaxPrefixesV4 *MaxPrefixes
+	MaxPrefixesV6 *MaxPrefixes
+	// GracefulRestart overrides the mode of the router when set.
+	GracefulRestart string
+	// ActivateEVPN activates the l2vpn evpn address family for the neighbor.
+	ActivateEVPN bool
+	VRFName      string
+	Incoming     AllowedIn
+	Outgoing     AllowedOut
+	Sources      []string
 }
 
+// Peer returns the way the neighbor is referenced in the FRR configuration,
+// which is its address, the interface for unnumbered sessions or the peer-group
+// for dynamic neighbors.
+func (n *NeighborConfig) Peer() string {
+	if n.Iface != "" {
+		return n.Iface
+	}
+	if n.ListenRange != "" {
+		return "dyn-" + strings.ReplaceAll(n.ListenRange, "/", "-")
+	}
+	return n.Addr
+}
+
+// ID returns the identifier of the neighbor used when naming its route maps
+// and prefix lists.
 func (n *NeighborConfig) ID() string {
 	if n.VRFName == "" {
-		return n.Addr
+		return n.Peer()
 	}
-	return fmt.Sprintf("%s-%s", n.Addr, n.VRFName)
+	return fmt.Sprintf("%s-%s", n.Peer(), n.VRFName)
+}
+
+// MaxPrefixes is the maximum number of prefixes accepted from a neighbor for
+// an address family.
+type MaxPrefixes struct {
+	Limit          uint32
+	Threshold      uint32
+	WarningOnly    bool
+	RestartMinutes uint32
+}
+
+// Value returns the arguments of the neighbor maximum-prefix command.
+func (m *MaxPrefixes) Value() string {
+	res := strconv.FormatUint(uint64(m.Limit), 10)
+	if m.Threshold != 0 {
+		res = fmt.Sprintf("%s %d", res, m.Threshold)
+	}
+	if m.WarningOnly {
+		return res + " warning-only"
+	}
+	if m.RestartMinutes != 0 {
+		res = fmt.Sprintf("%s restart %d", res, m.RestartMinutes)
+	}
+	return res
 }
 
 type AllowedIn struct {
 	All        bool
 	PrefixesV4 []IncomingFilter
 	PrefixesV6 []IncomingFilter
+	// RangesV4 and RangesV6 are ordered, and evaluated before the prefixes.
+	RangesV4 []IncomingFilter
+	RangesV6 []IncomingFilter
+	Policies []IncomingPolicy
 }
 
+// AllPrefixes returns the ranges and the prefixes, in the order they must be
+// evaluated.
 func (a *AllowedIn) AllPrefixes() []IncomingFilter {
-	return append(a.PrefixesV4, a.PrefixesV6...)
+	res := make([]IncomingFilter, 0, len(a.RangesV4)+len(a.PrefixesV4)+len(a.RangesV6)+len(a.PrefixesV6))
+	res = append(res, a.RangesV4...)
+	res = append(res, a.PrefixesV4...)
+	res = append(res, a.RangesV6...)
+	res = append(res, a.PrefixesV6...)
+	return res
 }
 
 type AllowedOut struct {
 	PrefixesV4 []OutgoingFilter
 	PrefixesV6 []OutgoingFilter
+	// RedistributedV4 and RedistributedV6 select the redistributed routes
+	// advertised to the neighbor. They are ordered, and set only when the
+	// router redistributes routes of the family.
+	RedistributedV4 []IncomingFilter
+	RedistributedV6 []IncomingFilter
 }
 
 func (a *AllowedOut) AllPrefixes() []OutgoingFilter {
@@ -106,6 +365,86 @@ func (a *AllowedOut) AllPrefixes() []OutgoingFilter {
 type IncomingFilter struct {
 	IPFamily ipfamily.Family
 	Prefix   string
+	// GE and LE are the bounds of the length of the matched prefixes, if set.
+	GE      uint32
+	LE      uint32
+	Deny    bool
+	Sources []string
+}
+
+// Action returns the action of the prefix list entry.
+func (f IncomingFilter) Action() string {
+	if f.Deny {
+		return "deny"
+	}
+	return "permit"
+}
+
+// Match returns the prefix matched by the prefix list entry, with its bounds.
+func (f IncomingFilter) Match() string {
+	res := f.Prefix
+	if f.GE != 0 {
+		res = fmt.Sprintf("%s ge %d", res, f.GE)
+	}
+	if f.LE != 0 {
+		res = fmt.Sprintf("%s le %d", res, f.LE)
+	}
+	return res
+}
+
+// IncomingPolicy is a set of actions applied to the received routes matching
+// both its prefixes and its communities, when set.
+type IncomingPolicy struct {
+	PrefixesV4          []string
+	PrefixesV6          []string
+	Communities         []string
+	LargeCommunities    []string
+	LocalPref           *uint32
+	Weight              *uint32
+	SetCommunities      []string
+	SetLargeCommunities []string
+	StripCommunities    bool
+	Sources             []string
+}
+
+// PolicyMatch represents one of the route map entries a policy is rendered to.
+// FRR requires all the match clauses of an entry to be satisfied, so a policy
+// matching prefixes of both families or both kinds of communities needs one
+// entry for each combination.
+type PolicyMatch struct {
+	// IPFamily is empty when the entry doesn't match the prefixes.
+	IPFamily         ipfamily.Family
+	Communities      bool
+	LargeCommunities bool
+}
+
+// Matches returns the route map entries the policy is rendered to.
+func (p IncomingPolicy) Matches() []PolicyMatch {
+	families := []ipfamily.Family{}
+	if len(p.PrefixesV4) > 0 {
+		families = append(families, ipfamily.IPv4)
+	}
+	if len(p.PrefixesV6) > 0 {
+		families = append(families, ipfamily.IPv6)
+	}
+	if len(families) == 0 {
+		families = append(families, "")
+	}
+
+	res := []PolicyMatch{}
+	for _, f := range families {
+		if len(p.Communities) == 0 && len(p.LargeCommunities) == 0 {
+			res = append(res, PolicyMatch{IPFamily: f})
+			continue
+		}
+		if len(p.Communities) > 0 {
+			res = append(res, PolicyMatch{IPFamily: f, Communities: true})
+		}
+		if len(p.LargeCommunities) > 0 {
+			res = append(res, PolicyMatch{IPFamily: f, LargeCommunities: true})
+		}
+	}
+	return res
 }
 
 type OutgoingFilter struct {
@@ -113,7 +452,59 @@ type OutgoingFilter struct {
 	Prefix           string
 	Communities      []string
 	LargeCommunities []string
-	LocalPref        uint32
+	// ExtendedCommunities are in the "<type>:<administrator>:<assigned-number>" form.
+	ExtendedCommunities []string
+	LocalPref           uint32
+	ASPathPrepend       *ASPathPrepend
+	MED                 *uint32
+	Sources             []string
+}
+
+// ASPathPrepend represents the prepending of an AS number to the AS path.
+// Either the ASN or LastAS are set.
+type ASPathPrepend struct {
+	ASN    uint32
+	LastAS bool
+	Count  uint32
+}
+
+// Value returns the arguments of the set as-path prepend command.
+func (p *ASPathPrepend) Value() string {
+	if p.LastAS {
+		return fmt.Sprintf("last-as %d", p.Count)
+	}
+	asns := make([]string, 0, p.Count)
+	for i := uint32(0); i < p.Count; i++ {
+		asns = append(asns, strconv.FormatUint(uint64(p.ASN), 10))
+	}
+	return strings.Join(asns, " ")
+}
+
+// ID returns an identifier of the prepend, suitable to be used in prefix list names.
+func (p *ASPathPrepend) ID() string {
+	if p.LastAS {
+		return fmt.Sprintf("last-as-%d", p.Count)
+	}
+	return fmt.Sprintf("%dx%d", p.ASN, p.Count)
+}
+
+// extendedCommunityValue returns the arguments of the set extcommunity
+// command for the given "<type>:<administrator>:<assigned-number>" community.
+func extendedCommunityValue(community string) (string, error) {
+	fs := strings.SplitN(community, ":", 2)
+	if len(fs) != 2 {
+		return "", fmt.Errorf("invalid extended community %s", community)
+	}
+	switch fs[0] {
+	case "rt", "soo":
+		return fs[0] + " " + fs[1], nil
+	case "lb":
+		// FRR encodes the local AS as the administrator of the link bandwidth,
+		// which is validated to be the one given.
+		bw := fs[1][strings.LastIndex(fs[1], ":")+1:]
+		return "bandwidth " + bw, nil
+	}
+	return "", fmt.Errorf("unsupported extended community type %s", fs[0])
 }
 
 // templateConfig uses the template library to template
@@ -131,6 +522,12 @@ func templateConfig(data interface{}) (string, error) {
 				i++
 				return i
 			},
+			"sources": func(sources []string) string {
+				return strings.Join(sources, ", ")
+			},
+			"joinStrings": func(values []string) string {
+				return strings.Join(values, " ")
+			},
 			"frrIPFamily": func(ipFamily ipfamily.Family) string {
 				if ipFamily == "ipv6" {
 					return "ipv6"
@@ -140,21 +537,57 @@ func templateConfig(data interface{}) (string, error) {
 			"localPrefPrefixList": func(neighbor *NeighborConfig, localPreference uint32) string {
 				return fmt.Sprintf("%s-%d-%s-localpref-prefixes", neighbor.ID(), localPreference, neighbor.IPFamily)
 			},
+			"medPrefixList": func(neighbor *NeighborConfig, med uint32) string {
+				return fmt.Sprintf("%s-%d-%s-med-prefixes", neighbor.ID(), med, neighbor.IPFamily)
+			},
+			"asPathPrependPrefixList": func(neighbor *NeighborConfig, prepend *ASPathPrepend) string {
+				return fmt.Sprintf("%s-%s-%s-prepend-prefixes", neighbor.ID(), prepend.ID(), neighbor.IPFamily)
+			},
 			"communityPrefixList": func(neighbor *NeighborConfig, community string) string {
 				return fmt.Sprintf("%s-%s-%s-community-prefixes", neighbor.ID(), community, neighbor.IPFamily)
 			},
 			"largeCommunityPrefixList": func(neighbor *NeighborConfig, community string) string {
 				return fmt.Sprintf("%s-large:%s-%s-community-prefixes", neighbor.ID(), community, neighbor.IPFamily)
 			},
+			"extendedCommunityPrefixList": func(neighbor *NeighborConfig, community string) string {
+				return fmt.Sprintf("%s-ext:%s-%s-community-prefixes", neighbor.ID(), community, neighbor.IPFamily)
+			},
+			"extendedCommunityValue": extendedCommunityValue,
 			"allowedPrefixList": func(neighbor *NeighborConfig) string {
 				return fmt.Sprintf("%s-pl-%s", neighbor.ID(), neighbor.IPFamily)
 			},
 			"allowedIncomingList": func(neighbor *NeighborConfig) string {
 				return fmt.Sprintf("%s-inpl-%s", neighbor.ID(), neighbor.IPFamily)
 			},
-			"mustDisableConnectedCheck": func(ipFamily ipfamily.Family, myASN, asn uint32, eBGPMultiHop bool) bool {
+			"incomingPoliciesRouteMap": func(neighbor *NeighborConfig) string {
+				return fmt.Sprintf("%s-in-policies", neighbor.ID())
+			},
+			"incomingPolicyList": func(neighbor *NeighborConfig, index int, kind string) string {
+				return fmt.Sprintf("%s-in-policy-%d-%s", neighbor.ID(), index, kind)
+			},
+			"importPrefixList": func(router *RouterConfig, vrf string) string {
+				return fmt.Sprintf("%s-%s-prefixes", router.ImportRouteMap(), vrf)
+			},
+			"redistributeRouteMap": func(router *RouterConfig, r Redistribution) string {
+				vrf := router.VRF
+				if vrf == "" {
+					vrf = "default"
+				}
+				return fmt.Sprintf("%s-redistribute-%s-%s", vrf, r.IPFamily, r.Name())
+			},
+			"redistributedPrefixList": func(neighbor *NeighborConfig) string {
+				return fmt.Sprintf("%s-redistributed-pl", neighbor.ID())
+			},
+			"redistributedTag": func() uint32 {
+				return redistributedTag
+			},
+			"mustDisableConnectedCheck": func(ipFamily ipfamily.Family, myASN, asn uint32, dynamicASN string, eBGPMultiHop bool) bool {
+				isEBGP := myASN != asn
+				if dynamicASN != "" {
+					isEBGP = dynamicASN == "external"
+				}
 				// return true only for IPv6 eBGP sessions
-				if ipFamily == "ipv6" && myASN != asn && !eBGPMultiHop {
+				if ipFamily == "ipv6" && isEBGP && !eBGPMultiHop {
 					return true
 				}
 				return false
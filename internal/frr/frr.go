@@ -19,9 +19,22 @@ type ConfigHandler interface {
 	ApplyConfig(config *Config) error
 }
 
+type StatusFetcher interface {
+	GetStatus() Status
+}
+
+// Status represents the status of the FRR instance, as seen by the
+// daemon.
+type Status struct {
+	LastReloadResult string
+	RunningConfig    string
+}
+
 type FRR struct {
-	reloadConfig chan reloadEvent
-	logLevel     string
+	reloadConfig    chan reloadEvent
+	logLevel        string
+	status          Status
+	onStatusChanged func()
 	sync.Mutex
 }
 
@@ -45,38 +58,81 @@ func (f *FRR) ApplyConfig(config *Config) error {
 var debounceTimeout = 3 * time.Second
 var failureTimeout = time.Second * 5
 
-func NewFRR(ctx context.Context, logger log.Logger, logLevel logging.Level) *FRR {
+// GetStatus returns the latest status of the FRR instance.
+func (f *FRR) GetStatus() Status {
+	f.Lock()
+	defer f.Unlock()
+	return f.status
+}
+
+// NewFRR returns a new FRR config handler. The given onStatusChanged callback
+// is invoked every time the status returned by GetStatus changes.
+func NewFRR(ctx context.Context, onStatusChanged func(), logger log.Logger, logLevel logging.Level) *FRR {
 	res := &FRR{
-		reloadConfig: make(chan reloadEvent),
-		logLevel:     logLevelToFRR(logLevel),
+		reloadConfig:    make(chan reloadEvent),
+		logLevel:        logLevelToFRR(logLevel),
+		onStatusChanged: onStatusChanged,
 	}
 	reload := func(config *Config) error {
-		return generateAndReloadConfigFile(config, logger)
+		err := generateAndReloadConfigFile(config, logger)
+		if err != nil {
+			return err
+		}
+		running, err := templateConfig(config.Redacted())
+		if err != nil {
+			level.Error(logger).Log("op", "reload", "error", err, "cause", "template redacted config")
+			return nil
+		}
+		res.updateStatus(func(s *Status) {
+			s.RunningConfig = running
+		})
+		return nil
+	}
+
+	onReload := func(result string) {
+		res.updateStatus(func(s *Status) {
+			s.LastReloadResult = result
+		})
 	}
 
 	debouncer(ctx, reload, res.reloadConfig, debounceTimeout, failureTimeout, logger)
-	reloadValidator(ctx, logger, res.reloadConfig)
+	reloadValidator(ctx, logger, res.reloadConfig, onReload)
 	return res
 }
 
-func reloadValidator(ctx context.Context, l log.Logger, reload chan<- reloadEvent) {
+func (f *FRR) updateStatus(update func(s *Status)) {
+	f.Lock()
+	old := f.status
+	update(&f.status)
+	changed := old != f.status
+	f.Unlock()
+
+	if changed && f.onStatusChanged != nil {
+		f.onStatusChanged()
+	}
+}
+
+func reloadValidator(ctx context.Context, l log.Logger, reload chan<- reloadEvent, onReload func(result string)) {
 	var tickerIntervals = 30 * time.Second
 	var prevReloadTimeStamp string
 
 	ticker := time.NewTicker(tickerIntervals)
 	go func() {
-		select {
-		case <-ticker.C:
-			validateReload(l, &prevReloadTimeStamp, reload)
-		case <-ctx.Done():
-			return
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				validateReload(l, &prevReloadTimeStamp, reload, onReload)
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 }
 
 const statusFileName = "/etc/frr_reloader/.status"
 
-func validateReload(l log.Logger, prevReloadTimeStamp *string, reload chan<- reloadEvent) {
+func validateReload(l log.Logger, prevReloadTimeStamp *string, reload chan<- reloadEvent, onReload func(result string)) {
 	bytes, err := os.ReadFile(statusFileName)
 	if err != nil {
 		if !os.IsNotExist(err) {
@@ -97,6 +153,7 @@ func validateReload(l log.Logger, prevReloadTimeStamp *string, reload chan<- rel
 	}
 
 	*prevReloadTimeStamp = timeStamp
+	onReload(status)
 
 	if strings.Compare(status, "failure") == 0 {
 		level.Error(l).Log("op", "reload-validate", "error", fmt.Errorf("reload failure"),
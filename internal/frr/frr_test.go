@@ -97,7 +97,7 @@ func testCheckConfigFile(t *testing.T) {
 func TestSingleSession(t *testing.T) {
 	testSetup(t)
 	ctx, cancel := context.WithCancel(context.Background())
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 	defer cancel()
 
 	config := Config{
@@ -139,7 +139,7 @@ func TestSingleSession(t *testing.T) {
 func TestTwoRoutersTwoNeighbors(t *testing.T) {
 	testSetup(t)
 	ctx, cancel := context.WithCancel(context.Background())
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 	defer cancel()
 
 	config := Config{
@@ -212,7 +212,7 @@ func TestTwoRoutersTwoNeighbors(t *testing.T) {
 func TestTwoSessionsAcceptAll(t *testing.T) {
 	testSetup(t)
 	ctx, cancel := context.WithCancel(context.Background())
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 	defer cancel()
 
 	config := Config{
@@ -252,7 +252,7 @@ func TestTwoSessionsAcceptAll(t *testing.T) {
 func TestTwoSessionsAcceptSomeV4(t *testing.T) {
 	testSetup(t)
 	ctx, cancel := context.WithCancel(context.Background())
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 	defer cancel()
 
 	config := Config{
@@ -306,7 +306,7 @@ func TestTwoSessionsAcceptSomeV4(t *testing.T) {
 func TestTwoSessionsAcceptV4AndV6(t *testing.T) {
 	testSetup(t)
 	ctx, cancel := context.WithCancel(context.Background())
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 	defer cancel()
 
 	config := Config{
@@ -367,7 +367,7 @@ func TestSingleSessionWithEBGPMultihop(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 
 	config := Config{
 		Routers: []*RouterConfig{
@@ -413,7 +413,7 @@ func TestSingleSessionWithIPv6SingleHop(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 
 	config := Config{
 		Routers: []*RouterConfig{
@@ -455,7 +455,7 @@ func TestMultipleNeighborsOneV4AndOneV6(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 
 	config := Config{
 		Routers: []*RouterConfig{
@@ -512,7 +512,7 @@ func TestMultipleRoutersMultipleNeighs(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 
 	config := Config{
 		Routers: []*RouterConfig{
@@ -607,7 +607,7 @@ func TestSingleSessionWithEBGPMultihopAndExtras(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	frr := NewFRR(ctx, log.NewNopLogger(), logging.LevelInfo)
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
 
 	config := Config{
 		Routers: []*RouterConfig{
@@ -647,3 +647,853 @@ func TestSingleSessionWithEBGPMultihopAndExtras(t *testing.T) {
 
 	testCheckConfigFile(t)
 }
+
+func TestSingleSessionWithBFDAndTimers(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	receiveInterval := uint32(100)
+	detectMultiplier := uint32(5)
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily:      ipfamily.IPv4,
+						ASN:           65001,
+						Addr:          "192.168.1.2",
+						HoldTime:      90,
+						KeepaliveTime: 30,
+						BFDProfile:    "bfdprofile1",
+						Outgoing: AllowedOut{
+							PrefixesV4: []OutgoingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "192.169.1.0/24",
+								},
+							},
+						},
+					},
+				},
+				IPV4Prefixes: []string{"192.169.1.0/24"},
+			},
+		},
+		BFDProfiles: []BFDProfile{
+			{
+				Name:             "bfdprofile1",
+				ReceiveInterval:  &receiveInterval,
+				DetectMultiplier: &detectMultiplier,
+				EchoMode:         true,
+			},
+			{
+				Name:        "bfdprofile2",
+				PassiveMode: true,
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestSingleSessionWithSources(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+						Outgoing: AllowedOut{
+							PrefixesV4: []OutgoingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "192.169.1.0/24",
+									Sources:  []string{"ns1/cfg1", "ns2/cfg2"},
+								},
+							},
+						},
+						Incoming: AllowedIn{
+							PrefixesV4: []IncomingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "192.170.1.0/24",
+									Sources:  []string{"ns2/cfg2"},
+								},
+							},
+						},
+						Sources: []string{"ns1/cfg1", "ns2/cfg2"},
+					},
+				},
+				IPV4Prefixes: []string{"192.169.1.0/24"},
+				IPV6Prefixes: []string{"2001:db8::/64"},
+				Sources:      []string{"ns1/cfg1", "ns2/cfg2"},
+				PrefixSources: map[string][]string{
+					"192.169.1.0/24": {"ns1/cfg1"},
+					"2001:db8::/64":  {"ns2/cfg2"},
+				},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestSingleUnnumberedSession(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily:   ipfamily.DualStack,
+						DynamicASN: "external",
+						Iface:      "swp1",
+						Outgoing: AllowedOut{
+							PrefixesV4: []OutgoingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "192.169.1.0/24",
+								},
+							},
+							PrefixesV6: []OutgoingFilter{
+								{
+									IPFamily: ipfamily.IPv6,
+									Prefix:   "2001:db8::/64",
+								},
+							},
+						},
+					},
+				},
+				IPV4Prefixes: []string{"192.169.1.0/24"},
+				IPV6Prefixes: []string{"2001:db8::/64"},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestDynamicNeighbors(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN:       65000,
+				ListenLimit: 150,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily:    ipfamily.IPv4,
+						ASN:         65001,
+						ListenRange: "192.168.1.0/24",
+						Outgoing: AllowedOut{
+							PrefixesV4: []OutgoingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "192.169.1.0/24",
+								},
+							},
+						},
+						Incoming: AllowedIn{
+							All: true,
+						},
+					},
+					{
+						IPFamily:      ipfamily.IPv6,
+						DynamicASN:    "external",
+						ListenRange:   "2001:db8:1::/64",
+						HoldTime:      90,
+						KeepaliveTime: 30,
+					},
+				},
+				IPV4Prefixes: []string{"192.169.1.0/24"},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestSingleSessionWithExtendedCommunities(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+						Outgoing: AllowedOut{
+							PrefixesV4: []OutgoingFilter{
+								{
+									IPFamily:            ipfamily.IPv4,
+									Prefix:              "192.169.1.0/24",
+									Communities:         []string{"10:169"},
+									ExtendedCommunities: []string{"rt:65000:100", "soo:192.0.2.1:10"},
+								},
+								{
+									IPFamily:            ipfamily.IPv4,
+									Prefix:              "192.170.1.0/24",
+									ExtendedCommunities: []string{"lb:65000:1000"},
+								},
+							},
+						},
+					},
+				},
+				IPV4Prefixes: []string{"192.169.1.0/24", "192.170.1.0/24"},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestSingleSessionWithMEDAndASPathPrepend(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	med := uint32(0)
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+						Outgoing: AllowedOut{
+							PrefixesV4: []OutgoingFilter{
+								{
+									IPFamily:      ipfamily.IPv4,
+									Prefix:        "192.169.1.0/24",
+									MED:           &med,
+									ASPathPrepend: &ASPathPrepend{ASN: 65000, Count: 3},
+								},
+								{
+									IPFamily:      ipfamily.IPv4,
+									Prefix:        "192.170.1.0/24",
+									ASPathPrepend: &ASPathPrepend{LastAS: true, Count: 2},
+								},
+							},
+						},
+					},
+				},
+				IPV4Prefixes: []string{"192.169.1.0/24", "192.170.1.0/24"},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestSingleSessionWithIncomingPolicies(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	localPref := uint32(200)
+	weight := uint32(100)
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+						Incoming: AllowedIn{
+							PrefixesV4: []IncomingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "0.0.0.0/0",
+								},
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "192.169.1.0/24",
+								},
+							},
+							PrefixesV6: []IncomingFilter{
+								{
+									IPFamily: ipfamily.IPv6,
+									Prefix:   "::/0",
+								},
+							},
+							Policies: []IncomingPolicy{
+								{
+									PrefixesV4: []string{"0.0.0.0/0"},
+									PrefixesV6: []string{"::/0"},
+									LocalPref:  &localPref,
+								},
+								{
+									Communities:      []string{"65000:100"},
+									LargeCommunities: []string{"65000:1:1"},
+									Weight:           &weight,
+									StripCommunities: true,
+								},
+								{
+									PrefixesV4:     []string{"192.169.1.0/24"},
+									SetCommunities: []string{"65000:200", "65000:201"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestSingleSessionWithPrefixRanges(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+						Incoming: AllowedIn{
+							PrefixesV4: []IncomingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "192.169.1.0/24",
+								},
+							},
+							RangesV4: []IncomingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "10.1.0.0/16",
+									LE:       32,
+								},
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "10.0.0.0/8",
+									GE:       16,
+									LE:       32,
+									Deny:     true,
+								},
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "10.0.0.0/8",
+									GE:       32,
+								},
+							},
+							RangesV6: []IncomingFilter{
+								{
+									IPFamily: ipfamily.IPv6,
+									Prefix:   "2001:db8::/32",
+									GE:       64,
+									LE:       128,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestSingleSessionWithMaxPrefixes(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+						MaxPrefixesV4: &MaxPrefixes{
+							Limit:          1000,
+							Threshold:      80,
+							RestartMinutes: 5,
+						},
+						MaxPrefixesV6: &MaxPrefixes{
+							Limit:       100,
+							WarningOnly: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestSingleSessionWithGracefulRestart(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				GracefulRestart: &GracefulRestart{
+					Mode:          "restart",
+					RestartTime:   60,
+					StalePathTime: 180,
+					LLGRStaleTime: 3600,
+				},
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+					},
+					{
+						IPFamily:        ipfamily.IPv4,
+						ASN:             65002,
+						Addr:            "192.168.1.3",
+						GracefulRestart: "helper",
+					},
+				},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestMultipleRoutersWithVRFLeaking(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN:        65000,
+				IPV4Prefixes: []string{"192.169.10.0/24"},
+				Imports: []VRFImport{
+					{VRF: "red", All: true},
+				},
+			},
+			{
+				MyASN: 65000,
+				VRF:   "red",
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+						VRFName:  "red",
+					},
+				},
+				Imports: []VRFImport{
+					{
+						VRF: "blue",
+						PrefixesV4: []IncomingFilter{
+							{IPFamily: ipfamily.IPv4, Prefix: "10.0.0.0/8", LE: 24},
+						},
+						PrefixesV6: []IncomingFilter{},
+						Sources:    []string{"default/leak"},
+					},
+					{VRF: "default", All: true},
+				},
+				VPN: &VPNLeak{
+					RouteDistinguisher: "65000:10",
+					ImportRouteTargets: []string{"65000:100", "65000:200"},
+					ExportRouteTargets: []string{"65000:100"},
+				},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestMultipleRoutersWithEVPN(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily:     ipfamily.IPv4,
+						ASN:          65001,
+						Addr:         "192.168.1.2",
+						ActivateEVPN: true,
+					},
+				},
+				EVPN: &EVPN{
+					AdvertiseAllVNI: true,
+				},
+			},
+			{
+				MyASN:        65000,
+				VRF:          "red",
+				IPV4Prefixes: []string{"192.169.10.0/24"},
+				EVPN: &EVPN{
+					VNI:                  100,
+					RouteDistinguisher:   "65000:100",
+					ImportRouteTargets:   []string{"65000:100"},
+					ExportRouteTargets:   []string{"65000:100"},
+					AdvertiseIPv4Unicast: true,
+					AdvertiseIPv6Unicast: true,
+				},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestOSPFRouters(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+					},
+				},
+			},
+		},
+		OSPFRouters: []*OSPFRouterConfig{
+			{
+				RouterID: "10.0.0.1",
+				Interfaces: []OSPFInterface{
+					{
+						Name:      "eth0",
+						Area:      "0.0.0.0",
+						Cost:      10,
+						AuthKeyID: 1,
+						AuthKey:   "secret",
+						Sources:   []string{"default/ospf"},
+					},
+					{
+						Name:    "lo",
+						Area:    "0.0.0.0",
+						Passive: true,
+					},
+				},
+				Prefixes: []string{"192.169.10.0/24", "192.169.11.0/24"},
+				Sources:  []string{"default/ospf"},
+			},
+			{
+				VRF: "red",
+				Interfaces: []OSPFInterface{
+					{
+						Name: "eth1",
+						Area: "0.0.0.1",
+					},
+				},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestStaticRoutes(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		StaticRoutes: []*StaticRoutesConfig{
+			{
+				Routes: []StaticRoute{
+					{
+						IPFamily: ipfamily.IPv4,
+						Prefix:   "192.169.10.0/24",
+						NextHop:  "192.168.1.2",
+						Distance: 10,
+						Sources:  []string{"default/static"},
+					},
+					{
+						IPFamily:   ipfamily.IPv4,
+						Prefix:     "192.169.11.0/24",
+						NextHop:    "192.168.1.3",
+						Interface:  "eth0",
+						BFDProfile: "fast",
+					},
+					{
+						IPFamily:  ipfamily.IPv4,
+						Prefix:    "192.169.12.0/24",
+						Blackhole: true,
+					},
+					{
+						IPFamily: ipfamily.IPv6,
+						Prefix:   "fc00:f553:ccd:e799::/64",
+						NextHop:  "fc00:f853:ccd:e793::2",
+					},
+				},
+			},
+			{
+				VRF: "red",
+				Routes: []StaticRoute{
+					{
+						IPFamily:  ipfamily.IPv4,
+						Prefix:    "192.169.20.0/24",
+						Interface: "eth1",
+						Sources:   []string{"default/static"},
+					},
+				},
+			},
+		},
+		BFDProfiles: []BFDProfile{
+			{
+				Name: "fast",
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
+
+func TestSingleSessionWithRedistribution(t *testing.T) {
+	testSetup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frr := NewFRR(ctx, func() {}, log.NewNopLogger(), logging.LevelInfo)
+
+	config := Config{
+		Routers: []*RouterConfig{
+			{
+				MyASN: 65000,
+				Neighbors: []*NeighborConfig{
+					{
+						IPFamily: ipfamily.IPv4,
+						ASN:      65001,
+						Addr:     "192.168.1.2",
+						Outgoing: AllowedOut{
+							PrefixesV4: []OutgoingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "192.169.10.0/24",
+								},
+							},
+							RedistributedV4: []IncomingFilter{
+								{
+									IPFamily: ipfamily.IPv4,
+									Prefix:   "0.0.0.0/0",
+									LE:       32,
+								},
+							},
+						},
+					},
+					{
+						IPFamily: ipfamily.IPv6,
+						ASN:      65001,
+						Addr:     "fc00:f853:ccd:e793::2",
+						Outgoing: AllowedOut{
+							RedistributedV6: []IncomingFilter{
+								{
+									IPFamily: ipfamily.IPv6,
+									Prefix:   "fc00:f553:ccd:e700::/56",
+									LE:       64,
+								},
+								{
+									IPFamily: ipfamily.IPv6,
+									Prefix:   "fc00:f553:ccd:e799::/64",
+									Deny:     true,
+								},
+							},
+						},
+					},
+				},
+				IPV4Prefixes: []string{"192.169.10.0/24"},
+				RedistributeV4: []Redistribution{
+					{
+						IPFamily: ipfamily.IPv4,
+						Source:   "connected",
+						Prefixes: []IncomingFilter{
+							{
+								IPFamily: ipfamily.IPv4,
+								Prefix:   "10.244.0.0/16",
+								GE:       24,
+							},
+						},
+						Communities:      []string{"10:100"},
+						LargeCommunities: []string{"123:456:789"},
+						Sources:          []string{"default/redistribute"},
+					},
+					{
+						IPFamily:      ipfamily.IPv4,
+						Source:        "table",
+						Table:         10,
+						LocalPref:     200,
+						ASPathPrepend: &ASPathPrepend{ASN: 65000, Count: 2},
+					},
+				},
+				RedistributeV6: []Redistribution{
+					{
+						IPFamily: ipfamily.IPv6,
+						Source:   "kernel",
+					},
+				},
+			},
+		},
+	}
+
+	err := frr.ApplyConfig(&config)
+	if err != nil {
+		t.Fatalf("Failed to apply config: %s", err)
+	}
+
+	testCheckConfigFile(t)
+}
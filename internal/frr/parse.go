@@ -8,6 +8,7 @@ import (
 	"net"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -23,6 +24,17 @@ type Neighbor struct {
 	Port           int
 	RemoteRouterID string
 	MsgStats       MessageStats
+	// MaxPrefixes holds the address families with a limit on the received
+	// prefixes, indexed by their FRR name (i.e. ipv4Unicast).
+	MaxPrefixes map[string]MaxPrefixesStatus
+}
+
+// MaxPrefixesStatus is the limit on the prefixes received for an address family,
+// together with the number of prefixes currently received.
+type MaxPrefixesStatus struct {
+	Limit               int
+	ThresholdPercentage int
+	Received            int
 }
 
 type Route struct {
@@ -32,8 +44,36 @@ type Route struct {
 	Origin      string
 }
 
+// EVPNRoute is a route of the l2vpn evpn table.
+type EVPNRoute struct {
+	RD string
+	// Prefix is the EVPN prefix, i.e. [5]:[0]:[24]:[192.168.10.0].
+	Prefix string
+	Type   int
+	// IP is the address carried by the route, nil if the route has none.
+	IP           net.IP
+	IPLen        int
+	NextHops     []net.IP
+	RouteTargets []string
+}
+
+// OSPFNeighbor is an OSPF adjacency of the router of a VRF.
+type OSPFNeighbor struct {
+	RouterID string
+	VRF      string
+	IP       net.IP
+	// Interface is the name of the interface the neighbor is reached through.
+	Interface string
+	// State is the state of the adjacency, i.e. Full.
+	State string
+	// Role is the role of the neighbor on the segment, i.e. DR.
+	Role string
+}
+
 const bgpConnected = "Established"
 
+const ospfFull = "Full"
+
 type FRRNeighbor struct {
 	RemoteAs          int          `json:"remoteAs"`
 	LocalAs           int          `json:"localAs"`
@@ -44,8 +84,10 @@ type FRRNeighbor struct {
 	MsgStats          MessageStats `json:"messageStats"`
 	VRFName           string       `json:"vrf"`
 	AddressFamilyInfo map[string]struct {
-		SentPrefixCounter     int `json:"sentPrefixCounter"`
-		AcceptedPrefixCounter int `json:"acceptedPrefixCounter"`
+		SentPrefixCounter          int `json:"sentPrefixCounter"`
+		AcceptedPrefixCounter      int `json:"acceptedPrefixCounter"`
+		PrefixAllowedMax           int `json:"prefixAllowedMax"`
+		PrefixAllowedWarningThresh int `json:"prefixAllowedWarningThresh"`
 	} `json:"addressFamilyInfo"`
 }
 
@@ -77,6 +119,25 @@ type FRRRoute struct {
 	} `json:"nexthops"`
 }
 
+type FRREVPNPath struct {
+	Valid             bool   `json:"valid"`
+	RouteType         int    `json:"routeType"`
+	IP                string `json:"ip"`
+	IPLen             int    `json:"ipLen"`
+	ExtendedCommunity struct {
+		String string `json:"string"`
+	} `json:"extendedCommunity"`
+	Nexthops []struct {
+		IP string `json:"ip"`
+	} `json:"nexthops"`
+}
+
+type FRROSPFNeighbor struct {
+	State     string `json:"nbrState"`
+	Address   string `json:"address"`
+	IfaceName string `json:"ifaceName"`
+}
+
 type BFDPeer struct {
 	Multihop                  bool   `json:"multihop"`
 	Peer                      string `json:"peer"`
@@ -141,6 +202,7 @@ func ParseNeighbour(vtyshRes string) (*Neighbor, error) {
 			Port:           n.PortForeign,
 			RemoteRouterID: n.RemoteRouterID,
 			MsgStats:       n.MsgStats,
+			MaxPrefixes:    maxPrefixesFor(n),
 		}, nil
 	}
 	return nil, errors.New("no peers were returned")
@@ -181,11 +243,32 @@ func ParseNeighbours(vtyshRes string) ([]*Neighbor, error) {
 			Port:           n.PortForeign,
 			RemoteRouterID: n.RemoteRouterID,
 			MsgStats:       n.MsgStats,
+			MaxPrefixes:    maxPrefixesFor(n),
 		})
 	}
 	return res, nil
 }
 
+// maxPrefixesFor returns the limits on the received prefixes of the
+// address families of the given neighbor.
+func maxPrefixesFor(n FRRNeighbor) map[string]MaxPrefixesStatus {
+	var res map[string]MaxPrefixesStatus
+	for family, s := range n.AddressFamilyInfo {
+		if s.PrefixAllowedMax == 0 {
+			continue
+		}
+		if res == nil {
+			res = map[string]MaxPrefixesStatus{}
+		}
+		res[family] = MaxPrefixesStatus{
+			Limit:               s.PrefixAllowedMax,
+			ThresholdPercentage: s.PrefixAllowedWarningThresh,
+			Received:            s.AcceptedPrefixCounter,
+		}
+	}
+	return res
+}
+
 // parseRoute takes the result of a show bgp ipv4 / ipv6
 // and parses the informations related to all the routes.
 func ParseRoutes(vtyshRes string) (map[string]Route, error) {
@@ -253,3 +336,180 @@ func ParseVRFs(vtyshRes string) ([]string, error) {
 	sort.Strings(res)
 	return res, nil
 }
+
+// ParseEVPNRoutes takes the result of a show bgp l2vpn evpn json
+// and parses the routes, sorted by route distinguisher and prefix.
+func ParseEVPNRoutes(vtyshRes string) ([]EVPNRoute, error) {
+	toParse := map[string]json.RawMessage{}
+	err := json.Unmarshal([]byte(vtyshRes), &toParse)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse vtysh response")
+	}
+
+	res := []EVPNRoute{}
+	for rd, raw := range toParse {
+		// The routes are grouped by route distinguisher, together with
+		// scalar fields such as the table version.
+		prefixes := map[string]json.RawMessage{}
+		if err := json.Unmarshal(raw, &prefixes); err != nil {
+			continue
+		}
+		for prefix, rawPrefix := range prefixes {
+			if prefix == "rd" {
+				continue
+			}
+			p := struct {
+				Paths json.RawMessage `json:"paths"`
+			}{}
+			if err := json.Unmarshal(rawPrefix, &p); err != nil {
+				return nil, errors.Wrapf(err, "failed to parse prefix %s", prefix)
+			}
+			paths, err := evpnPaths(p.Paths)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse the paths of prefix %s", prefix)
+			}
+			r, err := evpnRouteFor(rd, prefix, paths)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, r)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].RD != res[j].RD {
+			return res[i].RD < res[j].RD
+		}
+		return res[i].Prefix < res[j].Prefix
+	})
+	return res, nil
+}
+
+// evpnPaths parses the paths of an EVPN prefix, which depending on the FRR
+// version are either a list or a list of lists.
+func evpnPaths(raw json.RawMessage) ([]FRREVPNPath, error) {
+	res := []FRREVPNPath{}
+	if len(raw) == 0 {
+		return res, nil
+	}
+	err := json.Unmarshal(raw, &res)
+	if err == nil {
+		return res, nil
+	}
+	nested := [][]FRREVPNPath{}
+	if err := json.Unmarshal(raw, &nested); err != nil {
+		return nil, err
+	}
+	for _, p := range nested {
+		res = append(res, p...)
+	}
+	return res, nil
+}
+
+func evpnRouteFor(rd, prefix string, paths []FRREVPNPath) (EVPNRoute, error) {
+	res := EVPNRoute{
+		RD:           rd,
+		Prefix:       prefix,
+		NextHops:     make([]net.IP, 0),
+		RouteTargets: make([]string, 0),
+	}
+	for _, p := range paths {
+		res.Type = p.RouteType
+		res.IPLen = p.IPLen
+		if p.IP != "" {
+			res.IP = net.ParseIP(p.IP)
+			if res.IP == nil {
+				return EVPNRoute{}, fmt.Errorf("failed to parse ip %s of prefix %s", p.IP, prefix)
+			}
+		}
+	out:
+		for _, h := range p.Nexthops {
+			ip := net.ParseIP(h.IP)
+			if ip == nil {
+				return EVPNRoute{}, fmt.Errorf("failed to parse ip %s", h.IP)
+			}
+			for _, current := range res.NextHops {
+				if ip.Equal(current) {
+					continue out
+				}
+			}
+			res.NextHops = append(res.NextHops, ip)
+		}
+		for _, c := range strings.Fields(p.ExtendedCommunity.String) {
+			if !strings.HasPrefix(c, "RT:") {
+				continue
+			}
+			rt := strings.TrimPrefix(c, "RT:")
+			if containsString(res.RouteTargets, rt) {
+				continue
+			}
+			res.RouteTargets = append(res.RouteTargets, rt)
+		}
+	}
+	return res, nil
+}
+
+func containsString(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseOSPFNeighbors takes the result of a show ip ospf vrf all neighbor json
+// and parses the neighbors, sorted by VRF, router id and interface.
+func ParseOSPFNeighbors(vtyshRes string) ([]OSPFNeighbor, error) {
+	toParse := map[string]struct {
+		Neighbors map[string][]FRROSPFNeighbor `json:"neighbors"`
+	}{}
+	err := json.Unmarshal([]byte(vtyshRes), &toParse)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse vtysh response")
+	}
+
+	res := []OSPFNeighbor{}
+	for vrf, v := range toParse {
+		for routerID, neighbors := range v.Neighbors {
+			for _, n := range neighbors {
+				ip := net.ParseIP(n.Address)
+				if ip == nil {
+					return nil, fmt.Errorf("failed to parse address %s of ospf neighbor %s", n.Address, routerID)
+				}
+				// The state comes in the State/Role form, i.e. Full/DR.
+				state, role := n.State, ""
+				if i := strings.Index(n.State, "/"); i >= 0 {
+					state, role = n.State[:i], n.State[i+1:]
+				}
+				// The interface comes in the name:address form, i.e. eth0:192.168.1.1.
+				iface := n.IfaceName
+				if i := strings.Index(iface, ":"); i >= 0 {
+					iface = iface[:i]
+				}
+				res = append(res, OSPFNeighbor{
+					RouterID:  routerID,
+					VRF:       vrf,
+					IP:        ip,
+					Interface: iface,
+					State:     state,
+					Role:      role,
+				})
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].VRF != res[j].VRF {
+			return res[i].VRF < res[j].VRF
+		}
+		if res[i].RouterID != res[j].RouterID {
+			return res[i].RouterID < res[j].RouterID
+		}
+		return res[i].Interface < res[j].Interface
+	})
+	return res, nil
+}
+
+// Full tells if the adjacency with the neighbor is fully established.
+func (n OSPFNeighbor) Full() bool {
+	return n.State == ospfFull
+}
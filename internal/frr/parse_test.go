@@ -793,3 +793,232 @@ func TestVRFs(t *testing.T) {
 		t.Fatalf("unexpected vrf list: %s", cmp.Diff(parsed, expected))
 	}
 }
+
+func TestNeighbourMaxPrefixes(t *testing.T) {
+	sample := `{
+  "192.168.1.2":{
+    "remoteAs":65001,
+    "localAs":65000,
+    "bgpState":"Established",
+    "portForeign":179,
+    "addressFamilyInfo":{
+      "ipv4Unicast":{
+        "acceptedPrefixCounter":80,
+        "sentPrefixCounter":1,
+        "prefixAllowedMax":100,
+        "prefixAllowedWarningThresh":75
+      },
+      "ipv6Unicast":{
+        "acceptedPrefixCounter":3,
+        "sentPrefixCounter":1
+      }
+    }
+  }
+}`
+
+	n, err := ParseNeighbour(sample)
+	if err != nil {
+		t.Fatalf("Failed to parse %s", err)
+	}
+	expected := map[string]MaxPrefixesStatus{
+		"ipv4Unicast": {
+			Limit:               100,
+			ThresholdPercentage: 75,
+			Received:            80,
+		},
+	}
+	if !cmp.Equal(expected, n.MaxPrefixes) {
+		t.Fatal("unexpected max prefixes (-want +got)\n", cmp.Diff(expected, n.MaxPrefixes))
+	}
+}
+
+func TestEVPNRoutes(t *testing.T) {
+	sample := `{
+  "bgpTableVersion":3,
+  "bgpLocalRouterId":"10.0.0.1",
+  "defaultLocPrf":100,
+  "localAS":65000,
+  "65000:100":{
+    "rd":"65000:100",
+    "[5]:[0]:[24]:[192.168.10.0]":{
+      "prefix":"[5]:[0]:[24]:[192.168.10.0]",
+      "prefixLen":352,
+      "paths":[
+        {
+          "valid":true,
+          "bestpath":true,
+          "pathFrom":"external",
+          "routeType":5,
+          "ethTag":0,
+          "ipLen":24,
+          "ip":"192.168.10.0",
+          "locPrf":100,
+          "weight":32768,
+          "peerId":"(unspec)",
+          "origin":"IGP",
+          "extendedCommunity":{
+            "string":"RT:65000:100 ET:8 Rmac:aa:bb:cc:dd:ee:ff"
+          },
+          "nexthops":[
+            {
+              "ip":"10.0.0.1",
+              "afi":"ipv4",
+              "used":true
+            }
+          ]
+        }
+      ]
+    }
+  },
+  "10.0.0.2:2":{
+    "rd":"10.0.0.2:2",
+    "[2]:[0]:[48]:[aa:bb:cc:00:00:01]":{
+      "prefix":"[2]:[0]:[48]:[aa:bb:cc:00:00:01]",
+      "prefixLen":352,
+      "paths":[
+        [
+          {
+            "valid":true,
+            "routeType":2,
+            "ethTag":0,
+            "macLen":48,
+            "mac":"aa:bb:cc:00:00:01",
+            "extendedCommunity":{
+              "string":"RT:65001:200 ET:8"
+            },
+            "nexthops":[
+              {
+                "ip":"10.0.0.2",
+                "afi":"ipv4",
+                "used":true
+              }
+            ]
+          }
+        ],
+        [
+          {
+            "valid":true,
+            "routeType":2,
+            "ethTag":0,
+            "macLen":48,
+            "mac":"aa:bb:cc:00:00:01",
+            "extendedCommunity":{
+              "string":"RT:65001:200 ET:8"
+            },
+            "nexthops":[
+              {
+                "ip":"10.0.0.2",
+                "afi":"ipv4",
+                "used":true
+              }
+            ]
+          }
+        ]
+      ]
+    }
+  },
+  "numPrefix":2,
+  "totalPrefix":2
+}`
+
+	routes, err := ParseEVPNRoutes(sample)
+	if err != nil {
+		t.Fatalf("Failed to parse %s", err)
+	}
+	expected := []EVPNRoute{
+		{
+			RD:           "10.0.0.2:2",
+			Prefix:       "[2]:[0]:[48]:[aa:bb:cc:00:00:01]",
+			Type:         2,
+			NextHops:     []net.IP{net.ParseIP("10.0.0.2")},
+			RouteTargets: []string{"65001:200"},
+		},
+		{
+			RD:           "65000:100",
+			Prefix:       "[5]:[0]:[24]:[192.168.10.0]",
+			Type:         5,
+			IP:           net.ParseIP("192.168.10.0"),
+			IPLen:        24,
+			NextHops:     []net.IP{net.ParseIP("10.0.0.1")},
+			RouteTargets: []string{"65000:100"},
+		},
+	}
+	if !cmp.Equal(routes, expected) {
+		t.Fatalf("unexpected evpn routes: %s", cmp.Diff(expected, routes))
+	}
+}
+
+func TestOSPFNeighbors(t *testing.T) {
+	sample := `{
+  "default":{
+    "vrfName":"default",
+    "vrfId":0,
+    "neighbors":{
+      "10.0.0.2":[
+        {
+          "nbrPriority":1,
+          "nbrState":"Full/DR",
+          "converged":"Full",
+          "role":"DR",
+          "upTimeInMsec":126473,
+          "deadTimeMsecs":33526,
+          "address":"192.168.1.2",
+          "ifaceName":"eth0:192.168.1.1",
+          "retransmitCounter":0,
+          "requestCounter":0,
+          "dbSummaryCounter":0
+        }
+      ]
+    }
+  },
+  "red":{
+    "vrfName":"red",
+    "vrfId":5,
+    "neighbors":{
+      "10.0.0.3":[
+        {
+          "nbrPriority":1,
+          "nbrState":"ExStart/DROther",
+          "converged":"ExStart",
+          "role":"DROther",
+          "deadTimeMsecs":38720,
+          "address":"192.168.2.3",
+          "ifaceName":"eth1:192.168.2.1",
+          "retransmitCounter":0,
+          "requestCounter":0,
+          "dbSummaryCounter":0
+        }
+      ]
+    }
+  }
+}`
+
+	neighbors, err := ParseOSPFNeighbors(sample)
+	if err != nil {
+		t.Fatalf("Failed to parse %s", err)
+	}
+	expected := []OSPFNeighbor{
+		{
+			RouterID:  "10.0.0.2",
+			VRF:       "default",
+			IP:        net.ParseIP("192.168.1.2"),
+			Interface: "eth0",
+			State:     "Full",
+			Role:      "DR",
+		},
+		{
+			RouterID:  "10.0.0.3",
+			VRF:       "red",
+			IP:        net.ParseIP("192.168.2.3"),
+			Interface: "eth1",
+			State:     "ExStart",
+			Role:      "DROther",
+		},
+	}
+	if !cmp.Equal(neighbors, expected) {
+		t.Fatalf("unexpected ospf neighbors: %s", cmp.Diff(expected, neighbors))
+	}
+	if !neighbors[0].Full() || neighbors[1].Full() {
+		t.Fatalf("unexpected adjacency states: %v, %v", neighbors[0].Full(), neighbors[1].Full())
+	}
+}